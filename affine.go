@@ -0,0 +1,161 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ctessum/geom/proj"
+)
+
+// Affine is a two-dimensional affine transformation, which maps point
+// (x, y) to (A*x + B*y + C, D*x + E*y + F).
+type Affine struct {
+	A, B, C float64
+	D, E, F float64
+}
+
+// AffineIdentity returns the transformation that leaves points unchanged.
+func AffineIdentity() Affine {
+	return Affine{A: 1, E: 1}
+}
+
+// AffineTranslate returns a transformation that shifts points by dx and dy.
+func AffineTranslate(dx, dy float64) Affine {
+	return Affine{A: 1, C: dx, E: 1, F: dy}
+}
+
+// AffineScale returns a transformation that scales points by sx in the x
+// direction and sy in the y direction, relative to origin.
+func AffineScale(sx, sy float64, origin Point) Affine {
+	return aboutPoint(Affine{A: sx, E: sy}, origin)
+}
+
+// AffineRotate returns a transformation that rotates points
+// counterclockwise by angle radians around origin.
+func AffineRotate(angle float64, origin Point) Affine {
+	sin, cos := math.Sincos(angle)
+	return aboutPoint(Affine{A: cos, B: -sin, D: sin, E: cos}, origin)
+}
+
+// AffineShear returns a transformation that shears points relative to
+// origin, so that x is shifted by shx times the distance from origin in
+// the y direction and y is shifted by shy times the distance from origin
+// in the x direction.
+func AffineShear(shx, shy float64, origin Point) Affine {
+	return aboutPoint(Affine{A: 1, B: shx, D: shy, E: 1}, origin)
+}
+
+// aboutPoint returns a transformation that applies a relative to origin.
+func aboutPoint(a Affine, origin Point) Affine {
+	return AffineTranslate(-origin.X, -origin.Y).Compose(a).Compose(AffineTranslate(origin.X, origin.Y))
+}
+
+// Compose returns the transformation that applies a and then b.
+func (a Affine) Compose(b Affine) Affine {
+	return Affine{
+		A: b.A*a.A + b.B*a.D,
+		B: b.A*a.B + b.B*a.E,
+		C: b.A*a.C + b.B*a.F + b.C,
+		D: b.D*a.A + b.E*a.D,
+		E: b.D*a.B + b.E*a.E,
+		F: b.D*a.C + b.E*a.F + b.F,
+	}
+}
+
+// Inverse returns the transformation that reverses a. It returns an
+// error if a is not invertible, for example if it scales by zero.
+func (a Affine) Inverse() (Affine, error) {
+	det := a.A*a.E - a.B*a.D
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, fmt.Errorf("geom: affine transformation %v is not invertible", a)
+	}
+	return Affine{
+		A: a.E / det,
+		B: -a.B / det,
+		C: (a.B*a.F - a.E*a.C) / det,
+		D: -a.D / det,
+		E: a.A / det,
+		F: (a.D*a.C - a.A*a.F) / det,
+	}, nil
+}
+
+// TransformPoint returns p transformed by a.
+func (a Affine) TransformPoint(p Point) Point {
+	return Point{X: a.A*p.X + a.B*p.Y + a.C, Y: a.D*p.X + a.E*p.Y + a.F}
+}
+
+// Transformer returns a proj.Transformer that applies a.
+func (a Affine) Transformer() proj.Transformer {
+	return func(x, y float64) (float64, float64, error) {
+		p := a.TransformPoint(Point{X: x, Y: y})
+		return p.X, p.Y, nil
+	}
+}
+
+// Apply returns g transformed by a. As with the Transform methods,
+// *Bounds are returned as Polygons.
+func (a Affine) Apply(g Geom) Geom {
+	g2, err := g.Transform(a.Transformer())
+	if err != nil {
+		panic(err) // This shouldn't happen, because a never returns an error.
+	}
+	return g2
+}
+
+// FitAffine returns the affine transformation that best maps the control
+// points in from to the corresponding points in to, in a least-squares
+// sense, along with the root-mean-square distance between the transformed
+// from points and the to points. This can be used, for example, to
+// georeference a scanned map given the map and ground coordinates of
+// several locations. At least three control points that are not all
+// collinear are required.
+func FitAffine(from, to []Point) (Affine, float64, error) {
+	if len(from) != len(to) {
+		return Affine{}, math.NaN(), fmt.Errorf("geom: FitAffine: %d from points but %d to points", len(from), len(to))
+	}
+	if len(from) < 3 {
+		return Affine{}, math.NaN(), fmt.Errorf("geom: FitAffine: need at least 3 control points, have %d", len(from))
+	}
+
+	// Center the points to improve the conditioning of the problem.
+	n := float64(len(from))
+	var fc, tc Point
+	for i := range from {
+		fc.X += from[i].X / n
+		fc.Y += from[i].Y / n
+		tc.X += to[i].X / n
+		tc.Y += to[i].Y / n
+	}
+	var sxx, sxy, syy, sxX, syX, sxY, syY float64
+	for i := range from {
+		f, t := pointSubtract(from[i], fc), pointSubtract(to[i], tc)
+		sxx += f.X * f.X
+		sxy += f.X * f.Y
+		syy += f.Y * f.Y
+		sxX += f.X * t.X
+		syX += f.Y * t.X
+		sxY += f.X * t.Y
+		syY += f.Y * t.Y
+	}
+
+	// Solve the normal equations.
+	det := sxx*syy - sxy*sxy
+	if det <= 1e-12*(sxx*syy) {
+		return Affine{}, math.NaN(), fmt.Errorf("geom: FitAffine: control points are collinear")
+	}
+	a := Affine{
+		A: (syy*sxX - sxy*syX) / det,
+		B: (sxx*syX - sxy*sxX) / det,
+		D: (syy*sxY - sxy*syY) / det,
+		E: (sxx*syY - sxy*sxY) / det,
+	}
+	a.C = tc.X - a.A*fc.X - a.B*fc.Y
+	a.F = tc.Y - a.D*fc.X - a.E*fc.Y
+
+	var sumSq float64
+	for i := range from {
+		dd := d(a.TransformPoint(from[i]), to[i])
+		sumSq += dd * dd
+	}
+	return a, math.Sqrt(sumSq / n), nil
+}
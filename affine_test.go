@@ -0,0 +1,104 @@
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAffine(t *testing.T) {
+	tests := []struct {
+		a       Affine
+		p, want Point
+	}{
+		{a: AffineIdentity(), p: Point{1, 2}, want: Point{1, 2}},
+		{a: AffineTranslate(1, -2), p: Point{1, 2}, want: Point{2, 0}},
+		{a: AffineScale(2, 3, Point{}), p: Point{1, 2}, want: Point{2, 6}},
+		{a: AffineScale(2, 3, Point{1, 1}), p: Point{2, 2}, want: Point{3, 4}},
+		{a: AffineRotate(math.Pi/2, Point{}), p: Point{1, 0}, want: Point{0, 1}},
+		{a: AffineRotate(math.Pi, Point{1, 1}), p: Point{2, 1}, want: Point{0, 1}},
+		{a: AffineShear(1, 0, Point{}), p: Point{1, 2}, want: Point{3, 2}},
+		{a: AffineShear(0, 0.5, Point{0, 1}), p: Point{2, 2}, want: Point{2, 3}},
+		{a: AffineTranslate(1, 0).Compose(AffineScale(2, 2, Point{})), p: Point{1, 1}, want: Point{4, 2}},
+		{a: AffineScale(2, 2, Point{}).Compose(AffineTranslate(1, 0)), p: Point{1, 1}, want: Point{3, 2}},
+	}
+	for i, test := range tests {
+		have := test.a.TransformPoint(test.p)
+		if !pointSimilar(have, test.want, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+		inv, err := test.a.Inverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back := inv.TransformPoint(have); !pointSimilar(back, test.p, 1e-12) {
+			t.Errorf("%d inverse: have %v, want %v", i, back, test.p)
+		}
+	}
+}
+
+func TestAffineInverseSingular(t *testing.T) {
+	if _, err := AffineScale(0, 1, Point{}).Inverse(); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestAffineApply(t *testing.T) {
+	a := AffineTranslate(1, 2)
+	tests := []struct {
+		g, want Geom
+	}{
+		{g: LineString{{0, 0}, {1, 1}}, want: LineString{{1, 2}, {2, 3}}},
+		{g: Polygon{{{0, 0}, {1, 0}, {1, 1}}}, want: Polygon{{{1, 2}, {2, 2}, {2, 3}}}},
+		{
+			g:    &Bounds{Min: Point{0, 0}, Max: Point{1, 1}},
+			want: Polygon{{{1, 2}, {2, 2}, {2, 3}, {1, 3}}},
+		},
+	}
+	for i, test := range tests {
+		have := a.Apply(test.g)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+	x, y, err := a.Transformer()(3, 4)
+	if err != nil || x != 4 || y != 6 {
+		t.Errorf("transformer: have (%g, %g, %v), want (4, 6, <nil>)", x, y, err)
+	}
+}
+
+func TestFitAffine(t *testing.T) {
+	want := AffineRotate(0.3, Point{5, 5}).Compose(AffineScale(2, 3, Point{})).Compose(AffineTranslate(100, -50))
+	from := []Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {3, 7}}
+	to := make([]Point, len(from))
+	for i, p := range from {
+		to[i] = want.TransformPoint(p)
+	}
+	have, rms, err := FitAffine(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range [][2]float64{{have.A, want.A}, {have.B, want.B}, {have.C, want.C},
+		{have.D, want.D}, {have.E, want.E}, {have.F, want.F}} {
+		if math.Abs(v[0]-v[1]) > 1e-9 {
+			t.Errorf("have %v, want %v", have, want)
+			break
+		}
+	}
+	if rms > 1e-9 {
+		t.Errorf("rms: have %g, want 0", rms)
+	}
+
+	// With noisy control points, the error should be reported.
+	to[4].X += 1
+	if _, rms, _ = FitAffine(from, to); rms <= 0 {
+		t.Errorf("noisy rms: have %g, want > 0", rms)
+	}
+
+	if _, _, err := FitAffine(from[:2], to[:2]); err == nil {
+		t.Error("expected an error for too few points")
+	}
+	if _, _, err := FitAffine([]Point{{0, 0}, {1, 1}, {2, 2}}, to[:3]); err == nil {
+		t.Error("expected an error for collinear points")
+	}
+}
@@ -0,0 +1,315 @@
+// Package cluster groups points into clusters, for example to find hot
+// spots in point data or to reduce the number of markers shown on a map.
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ctessum/geom"
+	"github.com/ctessum/geom/index/rtree"
+)
+
+// Noise is the label given to points that do not belong to any cluster.
+const Noise = -1
+
+// A Cluster is a group of points.
+type Cluster struct {
+	// Members holds the indices of the points in the cluster.
+	Members []int
+
+	// Centroid is the mean location of the points in the cluster.
+	Centroid geom.Point
+
+	// Hull is the convex hull of the points in the cluster. It is nil if
+	// the cluster has fewer than three points that are not all on the
+	// same line.
+	Hull geom.Polygon
+}
+
+// Result holds the outcome of a clustering.
+type Result struct {
+	// Labels holds the index in Clusters of the cluster that each point
+	// belongs to, or Noise if the point does not belong to a cluster.
+	Labels []int
+
+	// Clusters holds the clusters, in the order they were found.
+	Clusters []Cluster
+}
+
+// newResult creates a Result from points and their cluster labels,
+// where there are n clusters.
+func newResult(points geom.MultiPoint, labels []int, n int) *Result {
+	r := &Result{Labels: labels, Clusters: make([]Cluster, n)}
+	for i, l := range labels {
+		if l != Noise {
+			r.Clusters[l].Members = append(r.Clusters[l].Members, i)
+		}
+	}
+	for i := range r.Clusters {
+		c := &r.Clusters[i]
+		mp := make(geom.MultiPoint, len(c.Members))
+		for j, m := range c.Members {
+			mp[j] = points[m]
+		}
+		c.Centroid = mean(mp)
+		c.Hull = mp.ConvexHull()
+	}
+	return r
+}
+
+// mean returns the mean location of points.
+func mean(points geom.MultiPoint) geom.Point {
+	var c geom.Point
+	for _, p := range points {
+		c.X += p.X
+		c.Y += p.Y
+	}
+	c.X /= float64(len(points))
+	c.Y /= float64(len(points))
+	return c
+}
+
+// point is a point stored in a spatial index.
+type point struct {
+	geom.Point
+	i int
+}
+
+// index is a spatial index of a set of points.
+type index struct {
+	points geom.MultiPoint
+	tree   *rtree.Rtree
+}
+
+func newIndex(points geom.MultiPoint) *index {
+	idx := &index{points: points, tree: rtree.NewTree(25, 50)}
+	for i, p := range points {
+		idx.tree.Insert(&point{Point: p, i: i})
+	}
+	return idx
+}
+
+// within returns the indices of the points that are no farther than
+// radius from point i.
+func (idx *index) within(i int, radius float64) []int {
+	p := idx.points[i]
+	b := &geom.Bounds{
+		Min: geom.Point{X: p.X - radius, Y: p.Y - radius},
+		Max: geom.Point{X: p.X + radius, Y: p.Y + radius},
+	}
+	var o []int
+	for _, g := range idx.tree.SearchIntersect(b) {
+		q := g.(*point)
+		if math.Hypot(q.X-p.X, q.Y-p.Y) <= radius {
+			o = append(o, q.i)
+		}
+	}
+	return o
+}
+
+// DBSCAN clusters points using the DBSCAN algorithm (Ester et al., 1996).
+// Points that have at least minPts points (including themselves) within
+// distance eps are core points, and core points within eps of each other
+// are in the same cluster. Other points within eps of a core point are
+// added to the cluster of that point, and the remaining points are Noise.
+func DBSCAN(points geom.MultiPoint, eps float64, minPts int) *Result {
+	if !(eps >= 0) {
+		panic(fmt.Errorf("cluster: invalid eps %g", eps))
+	}
+	if minPts < 1 {
+		panic(fmt.Errorf("cluster: invalid minPts %d", minPts))
+	}
+	idx := newIndex(points)
+	const unvisited = -2
+	labels := make([]int, len(points))
+	for i := range labels {
+		labels[i] = unvisited
+	}
+	var n int
+	for i := range points {
+		if labels[i] != unvisited {
+			continue
+		}
+		neighbors := idx.within(i, eps)
+		if len(neighbors) < minPts {
+			labels[i] = Noise
+			continue
+		}
+		labels[i] = n
+		queue := neighbors
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+			if labels[j] == Noise {
+				// Border point.
+				labels[j] = n
+			}
+			if labels[j] != unvisited {
+				continue
+			}
+			labels[j] = n
+			if jNeighbors := idx.within(j, eps); len(jNeighbors) >= minPts {
+				queue = append(queue, jNeighbors...)
+			}
+		}
+		n++
+	}
+	return newResult(points, labels, n)
+}
+
+// KMeans divides points into k clusters using Lloyd's algorithm, so that
+// each point belongs to the cluster with the nearest centroid. The initial
+// centroids are chosen with the k-means++ method using rng, and iteration
+// stops when the clusters no longer change or after maxIterations
+// iterations.
+func KMeans(points geom.MultiPoint, k, maxIterations int, rng *rand.Rand) *Result {
+	if k < 1 || k > len(points) {
+		panic(fmt.Errorf("cluster: invalid number of clusters %d for %d points", k, len(points)))
+	}
+	centroids := initCentroids(points, k, rng)
+	labels := make([]int, len(points))
+	for i := range labels {
+		labels[i] = Noise
+	}
+	for iter := 0; iter < maxIterations; iter++ {
+		changed := false
+		for i, p := range points {
+			if l := nearest(p, centroids); l != labels[i] {
+				labels[i] = l
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		counts := make([]int, k)
+		sums := make([]geom.Point, k)
+		for i, p := range points {
+			counts[labels[i]]++
+			sums[labels[i]].X += p.X
+			sums[labels[i]].Y += p.Y
+		}
+		for c := range centroids {
+			if counts[c] == 0 {
+				// Move the centroid of an empty cluster to the point that
+				// is farthest from its own centroid.
+				far, farDist := 0, -1.
+				for i, p := range points {
+					if dd := dist2(p, centroids[labels[i]]); dd > farDist && counts[labels[i]] > 1 {
+						far, farDist = i, dd
+					}
+				}
+				counts[labels[far]]--
+				labels[far] = c
+				centroids[c] = points[far]
+				continue
+			}
+			centroids[c] = geom.Point{
+				X: sums[c].X / float64(counts[c]),
+				Y: sums[c].Y / float64(counts[c]),
+			}
+		}
+	}
+	for i, p := range points {
+		if labels[i] == Noise {
+			labels[i] = nearest(p, centroids)
+		}
+	}
+	return newResult(points, labels, k)
+}
+
+// initCentroids chooses k initial centroids from points using the
+// k-means++ method.
+func initCentroids(points geom.MultiPoint, k int, rng *rand.Rand) []geom.Point {
+	centroids := []geom.Point{points[rng.Intn(len(points))]}
+	dists := make([]float64, len(points))
+	for len(centroids) < k {
+		var sum float64
+		for i, p := range points {
+			dists[i] = dist2(p, centroids[nearest(p, centroids)])
+			sum += dists[i]
+		}
+		if sum == 0 {
+			// All remaining points coincide with a centroid.
+			centroids = append(centroids, points[rng.Intn(len(points))])
+			continue
+		}
+		r := rng.Float64() * sum
+		i := 0
+		for ; i < len(points)-1; i++ {
+			if r -= dists[i]; r < 0 && dists[i] > 0 {
+				break
+			}
+		}
+		centroids = append(centroids, points[i])
+	}
+	return centroids
+}
+
+// nearest returns the index of the centroid that is closest to p.
+func nearest(p geom.Point, centroids []geom.Point) int {
+	var o int
+	minDist := math.Inf(1)
+	for i, c := range centroids {
+		if dd := dist2(p, c); dd < minDist {
+			o, minDist = i, dd
+		}
+	}
+	return o
+}
+
+func dist2(p, q geom.Point) float64 {
+	return (p.X-q.X)*(p.X-q.X) + (p.Y-q.Y)*(p.Y-q.Y)
+}
+
+// Grid clusters points by dividing the plane into square cells with sides
+// of length cellSize, starting from the origin, and grouping the points
+// that are in the same cell. Clusters are ordered by their first point.
+func Grid(points geom.MultiPoint, cellSize float64) *Result {
+	if !(cellSize > 0) {
+		panic(fmt.Errorf("cluster: invalid cell size %g", cellSize))
+	}
+	type cell struct{ i, j float64 }
+	cells := make(map[cell]int)
+	labels := make([]int, len(points))
+	for i, p := range points {
+		c := cell{math.Floor(p.X / cellSize), math.Floor(p.Y / cellSize)}
+		l, ok := cells[c]
+		if !ok {
+			l = len(cells)
+			cells[c] = l
+		}
+		labels[i] = l
+	}
+	return newResult(points, labels, len(cells))
+}
+
+// Distance clusters points for display as map markers. Each point that is
+// not yet in a cluster, in order, starts a new cluster, which takes in
+// all of the other points within radius of it that are not yet in a
+// cluster.
+func Distance(points geom.MultiPoint, radius float64) *Result {
+	if !(radius >= 0) {
+		panic(fmt.Errorf("cluster: invalid radius %g", radius))
+	}
+	idx := newIndex(points)
+	labels := make([]int, len(points))
+	for i := range labels {
+		labels[i] = Noise
+	}
+	var n int
+	for i := range points {
+		if labels[i] != Noise {
+			continue
+		}
+		for _, j := range idx.within(i, radius) {
+			if labels[j] == Noise {
+				labels[j] = n
+			}
+		}
+		n++
+	}
+	return newResult(points, labels, n)
+}
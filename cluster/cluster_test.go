@@ -0,0 +1,102 @@
+package cluster
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ctessum/geom"
+)
+
+// blobs returns two groups of points around (0, 0) and (10, 10) and an
+// outlier at (5, -20).
+func blobs() geom.MultiPoint {
+	var mp geom.MultiPoint
+	for _, c := range []geom.Point{{X: 0, Y: 0}, {X: 10, Y: 10}} {
+		mp = append(mp,
+			geom.Point{X: c.X, Y: c.Y},
+			geom.Point{X: c.X + 1, Y: c.Y},
+			geom.Point{X: c.X, Y: c.Y + 1},
+			geom.Point{X: c.X + 1, Y: c.Y + 1},
+			geom.Point{X: c.X + 0.5, Y: c.Y + 0.5},
+		)
+	}
+	return append(mp, geom.Point{X: 5, Y: -20})
+}
+
+func TestDBSCAN(t *testing.T) {
+	r := DBSCAN(blobs(), 1, 3)
+	want := []int{0, 0, 0, 0, 0, 1, 1, 1, 1, 1, Noise}
+	if !reflect.DeepEqual(r.Labels, want) {
+		t.Errorf("labels: have %v, want %v", r.Labels, want)
+	}
+	if len(r.Clusters) != 2 {
+		t.Fatalf("have %d clusters, want 2", len(r.Clusters))
+	}
+	centroids := []geom.Point{{X: 0.5, Y: 0.5}, {X: 10.5, Y: 10.5}}
+	for i, c := range r.Clusters {
+		if !c.Centroid.Similar(centroids[i], 1e-12) {
+			t.Errorf("%d: centroid: have %v, want %v", i, c.Centroid, centroids[i])
+		}
+		if a := c.Hull.Area(); a != 1 {
+			t.Errorf("%d: hull area: have %g, want 1", i, a)
+		}
+	}
+}
+
+func TestDBSCAN_border(t *testing.T) {
+	// The last point is within eps of a core point but is not a core point
+	// itself.
+	mp := geom.MultiPoint{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}
+	r := DBSCAN(mp, 1, 3)
+	want := []int{0, 0, 0, 0}
+	if !reflect.DeepEqual(r.Labels, want) {
+		t.Errorf("have %v, want %v", r.Labels, want)
+	}
+	if r.Clusters[0].Hull != nil {
+		t.Errorf("hull of collinear points: have %v, want nil", r.Clusters[0].Hull)
+	}
+}
+
+func TestKMeans(t *testing.T) {
+	mp := blobs()[:10]
+	r := KMeans(mp, 2, 100, rand.New(rand.NewSource(1)))
+	for i := range mp {
+		if r.Labels[i] != r.Labels[i/5*5] {
+			t.Errorf("%d: have label %d, want %d", i, r.Labels[i], r.Labels[i/5*5])
+		}
+	}
+	if r.Labels[0] == r.Labels[5] {
+		t.Errorf("both groups have label %d", r.Labels[0])
+	}
+	for i, c := range r.Clusters {
+		if len(c.Members) != 5 {
+			t.Errorf("%d: have %d members, want 5", i, len(c.Members))
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	r := Grid(blobs(), 5)
+	want := []int{0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 2}
+	if !reflect.DeepEqual(r.Labels, want) {
+		t.Errorf("have %v, want %v", r.Labels, want)
+	}
+	r = Grid(blobs(), 0.75)
+	want = []int{0, 1, 2, 3, 0, 4, 5, 6, 7, 7, 8}
+	if !reflect.DeepEqual(r.Labels, want) {
+		t.Errorf("have %v, want %v", r.Labels, want)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	mp := geom.MultiPoint{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 10, Y: 0}}
+	r := Distance(mp, 1)
+	want := []int{0, 0, 1, 1, 2}
+	if !reflect.DeepEqual(r.Labels, want) {
+		t.Errorf("have %v, want %v", r.Labels, want)
+	}
+	if c := r.Clusters[1].Centroid; c != (geom.Point{X: 2.5, Y: 0}) {
+		t.Errorf("centroid: have %v, want (2.5, 0)", c)
+	}
+}
@@ -0,0 +1,537 @@
+// Package contour extracts isolines and isobands from gridded data using
+// the marching squares algorithm.
+package contour
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ctessum/geom"
+	"github.com/ctessum/geom/internal/robust"
+)
+
+// Grid holds regularly gridded data. The outer axis of the data is the y
+// (south-north) axis and the inner axis is the x (west-east) axis, so the
+// value in row j and column i is Data[j*NX+i], and the first row is the
+// southernmost one. Each value is located at the center of its grid cell.
+// carto.NewCanvasFromRaster puts the first row at the top of the image
+// unless flipVertical is true, so flipVertical must be true to draw a
+// Grid with north at the top.
+type Grid struct {
+	// S and W are the southern and western edges of the grid.
+	S, W float64
+
+	// DY and DX are the sizes of the grid cells in the y and x directions.
+	DY, DX float64
+
+	// NY and NX are the numbers of rows and columns in the grid.
+	NY, NX int
+
+	// Data holds the values in the grid. NaN values are treated as
+	// missing.
+	Data []float64
+}
+
+// NewGrid returns a new grid with southern edge S, western edge W, cell
+// sizes dy and dx, ny rows and nx columns, and values data.
+func NewGrid(S, W, dy, dx float64, ny, nx int, data []float64) *Grid {
+	if !(dy > 0) || !(dx > 0) {
+		panic(fmt.Errorf("contour: invalid cell size %g×%g", dx, dy))
+	}
+	if len(data) != nx*ny {
+		panic(fmt.Errorf("contour: data length %d doesn't match grid size %d×%d", len(data), nx, ny))
+	}
+	return &Grid{S: S, W: W, DY: dy, DX: dx, NY: ny, NX: nx, Data: data}
+}
+
+// vertex is a location and the value of the grid there.
+type vertex struct {
+	p geom.Point
+	v float64
+}
+
+// triangles calls f for each of the triangles that the grid is divided
+// into. Each square formed by the centers of four neighboring cells is
+// divided into four triangles around its center, where the value is the
+// mean of the values at the corners. This resolves the ambiguous saddle
+// cases of marching squares. The vertices of each triangle are in
+// counterclockwise order. Squares with missing values at any of their
+// corners are skipped.
+func (g *Grid) triangles(f func(t [3]vertex)) {
+	at := func(i, j int) vertex {
+		return vertex{
+			p: geom.Point{X: g.W + (float64(i)+0.5)*g.DX, Y: g.S + (float64(j)+0.5)*g.DY},
+			v: g.Data[j*g.NX+i],
+		}
+	}
+	for j := 0; j < g.NY-1; j++ {
+		for i := 0; i < g.NX-1; i++ {
+			c := [4]vertex{at(i, j), at(i+1, j), at(i+1, j+1), at(i, j+1)}
+			if math.IsNaN(c[0].v + c[1].v + c[2].v + c[3].v) {
+				continue
+			}
+			center := vertex{
+				p: geom.Point{X: g.W + float64(i+1)*g.DX, Y: g.S + float64(j+1)*g.DY},
+				v: (c[0].v + c[1].v + c[2].v + c[3].v) / 4,
+			}
+			for k := 0; k < 4; k++ {
+				f([3]vertex{c[k], c[(k+1)%4], center})
+			}
+		}
+	}
+}
+
+// crossing returns the point along the edge between a and b where the
+// value is t. The result does not depend on the order of a and b, so that
+// triangles that share an edge find the same crossing point.
+func crossing(a, b vertex, t float64) geom.Point {
+	if b.p.X < a.p.X || (b.p.X == a.p.X && b.p.Y < a.p.Y) {
+		a, b = b, a
+	}
+	f := (t - a.v) / (b.v - a.v)
+	switch {
+	case f <= 0:
+		return a.p
+	case f >= 1:
+		return b.p
+	}
+	return geom.Point{X: a.p.X + f*(b.p.X-a.p.X), Y: a.p.Y + f*(b.p.Y-a.p.Y)}
+}
+
+// Isolines returns the lines along which the gridded values equal each of
+// the thresholds. The lines run between the centers of the grid cells,
+// with values interpolated linearly between them. If smoothIterations is
+// greater than zero, the lines are smoothed with that many iterations of
+// Chaikin's algorithm (see geom.Smooth).
+func (g *Grid) Isolines(thresholds []float64, smoothIterations int) []geom.MultiLineString {
+	o := make([]geom.MultiLineString, len(thresholds))
+	for k, t := range thresholds {
+		var segs geom.MultiLineString
+		g.triangles(func(tri [3]vertex) {
+			var pts []geom.Point
+			for e := 0; e < 3; e++ {
+				a, b := tri[e], tri[(e+1)%3]
+				if (a.v >= t) != (b.v >= t) {
+					pts = append(pts, crossing(a, b, t))
+				}
+			}
+			if len(pts) == 2 && pts[0] != pts[1] {
+				segs = append(segs, geom.LineString(pts))
+			}
+		})
+		lines := segs.LineMerge()
+		if smoothIterations > 0 {
+			for i, l := range lines {
+				if len(l) > 3 && l[0] == l[len(l)-1] {
+					// Smooth closed lines as rings so they stay closed
+					// without a corner at their ends.
+					p := geom.Smooth(geom.Polygon{geom.Path(l)}, geom.Chaikin, smoothIterations).(geom.Polygon)
+					lines[i] = geom.LineString(p[0])
+				} else {
+					lines[i] = geom.Smooth(l, geom.Chaikin, smoothIterations).(geom.LineString)
+				}
+			}
+		}
+		o[k] = lines
+	}
+	return o
+}
+
+// Isobands returns polygons covering the areas where the gridded values
+// are between each pair of consecutive thresholds, which must be in
+// increasing order. Band k covers values v where
+// thresholds[k] <= v < thresholds[k+1], except that the last band also
+// includes values equal to the last threshold. Thresholds of -Inf or +Inf
+// can be used to create bands that are open ended.
+//
+// The polygons cover the area between the centers of the grid cells, with
+// values interpolated linearly between them, and are valid: shells are
+// counterclockwise, holes are clockwise, and rings only touch at points.
+// If smoothIterations is greater than zero, the polygons are smoothed with
+// that many iterations of Chaikin's algorithm (see geom.Smooth). Vertices
+// at the centers of grid cells, such as those along the edge of the grid,
+// are not moved, so neighboring bands still fit together, except where a
+// polygon is left unsmoothed because smoothing would make it invalid.
+func (g *Grid) Isobands(thresholds []float64, smoothIterations int) []geom.MultiPolygon {
+	for i := 1; i < len(thresholds); i++ {
+		if !(thresholds[i] > thresholds[i-1]) {
+			panic(fmt.Errorf("contour: thresholds must be increasing: %v", thresholds))
+		}
+	}
+	if len(thresholds) < 2 {
+		return nil
+	}
+	o := make([]geom.MultiPolygon, len(thresholds)-1)
+	for k := range o {
+		lo, hi := thresholds[k], thresholds[k+1]
+		last := k == len(o)-1
+		var e edgeSet
+		g.triangles(func(tri [3]vertex) {
+			piece := bandPiece(tri, lo, hi, last)
+			if len(piece) < 3 || ringArea(piece) <= 0 {
+				return
+			}
+			for i, p := range piece {
+				e.add(p, piece[(i+1)%len(piece)])
+			}
+		})
+		mp := assemble(e.rings())
+		if smoothIterations > 0 {
+			for i, p := range mp {
+				mp[i] = g.smoothPolygon(p, smoothIterations)
+			}
+		}
+		o[k] = mp
+	}
+	return o
+}
+
+// bandPiece returns the part of triangle tri where the value is between
+// lo and hi, as an open ring in counterclockwise order.
+func bandPiece(tri [3]vertex, lo, hi float64, last bool) []geom.Point {
+	if tri[0].v == tri[1].v && tri[1].v == tri[2].v {
+		// Flat triangles are either entirely inside or outside the band.
+		if v := tri[0].v; lo <= v && (v < hi || last && v == hi) {
+			return []geom.Point{tri[0].p, tri[1].p, tri[2].p}
+		}
+		return nil
+	}
+	// Because the value varies linearly within the triangle, the band is
+	// a convex polygon whose vertices are the corners of the triangle that
+	// are within the band and the points where the edges cross lo and hi.
+	var o []geom.Point
+	add := func(p geom.Point) {
+		if len(o) == 0 || o[len(o)-1] != p {
+			o = append(o, p)
+		}
+	}
+	for e := 0; e < 3; e++ {
+		a, b := tri[e], tri[(e+1)%3]
+		if a.v >= lo && a.v <= hi {
+			add(a.p)
+		}
+		levels := []float64{lo, hi}
+		if a.v > b.v {
+			levels = []float64{hi, lo}
+		}
+		for _, t := range levels {
+			if (a.v-t)*(b.v-t) < 0 {
+				add(crossing(a, b, t))
+			}
+		}
+	}
+	if len(o) > 1 && o[0] == o[len(o)-1] {
+		o = o[:len(o)-1]
+	}
+	return o
+}
+
+// ringArea returns the signed area of open ring r, which is positive if r
+// is counterclockwise.
+func ringArea(r []geom.Point) float64 {
+	var a float64
+	for i, p := range r {
+		q := r[(i+1)%len(r)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a / 2
+}
+
+// edgeSet is a set of directed edges where adding an edge removes its
+// reverse if it is already present, so that the edges shared between
+// neighboring pieces of a polygon cancel out and only the edges on the
+// boundary remain.
+type edgeSet struct {
+	edges []segment
+	alive []bool
+	index map[segment]int
+}
+
+type segment struct{ a, b geom.Point }
+
+func (e *edgeSet) add(a, b geom.Point) {
+	if e.index == nil {
+		e.index = make(map[segment]int)
+	}
+	if i, ok := e.index[segment{b, a}]; ok {
+		e.alive[i] = false
+		delete(e.index, segment{b, a})
+		return
+	}
+	e.index[segment{a, b}] = len(e.edges)
+	e.edges = append(e.edges, segment{a, b})
+	e.alive = append(e.alive, true)
+}
+
+// rings joins the remaining edges into open rings that do not touch
+// themselves.
+func (e *edgeSet) rings() [][]geom.Point {
+	out := make(map[geom.Point][]int)
+	for i, s := range e.edges {
+		if e.alive[i] {
+			out[s.a] = append(out[s.a], i)
+		}
+	}
+	used := make([]bool, len(e.edges))
+	var o [][]geom.Point
+	for i, s := range e.edges {
+		if !e.alive[i] || used[i] {
+			continue
+		}
+		ring := []geom.Point{s.a}
+		cur := i
+		for {
+			used[cur] = true
+			p := e.edges[cur].b
+			if p == s.a {
+				break
+			}
+			ring = append(ring, p)
+			// Where more than one edge leaves a point, take the one that
+			// turns furthest to the left.
+			din := sub(p, e.edges[cur].a)
+			next, best := -1, math.Inf(-1)
+			for _, j := range out[p] {
+				if used[j] {
+					continue
+				}
+				dout := sub(e.edges[j].b, p)
+				turn := math.Atan2(din.X*dout.Y-din.Y*dout.X, din.X*dout.X+din.Y*dout.Y)
+				if turn > best {
+					next, best = j, turn
+				}
+			}
+			if next < 0 {
+				break
+			}
+			cur = next
+		}
+		o = append(o, splitRing(ring)...)
+	}
+	return o
+}
+
+func sub(a, b geom.Point) geom.Point { return geom.Point{X: a.X - b.X, Y: a.Y - b.Y} }
+
+// splitRing splits open ring r into separate rings wherever it passes
+// through the same point more than once.
+func splitRing(r []geom.Point) [][]geom.Point {
+	seen := make(map[geom.Point]int)
+	for j, p := range r {
+		if i, ok := seen[p]; ok {
+			inner := append([]geom.Point{}, r[i:j]...)
+			outer := append(append([]geom.Point{}, r[:i]...), r[j:]...)
+			return append(splitRing(inner), splitRing(outer)...)
+		}
+		seen[p] = j
+	}
+	return [][]geom.Point{r}
+}
+
+// removeCollinear removes the points in open ring r that are on straight
+// lines between their neighbors.
+func removeCollinear(r []geom.Point) []geom.Point {
+	for changed := true; changed && len(r) >= 3; {
+		changed = false
+		o := r[:0:0]
+		for i, p := range r {
+			prev, next := r[(i+len(r)-1)%len(r)], r[(i+1)%len(r)]
+			d0, d1 := sub(p, prev), sub(next, p)
+			if d0.X*d1.Y-d0.Y*d1.X == 0 && d0.X*d1.X+d0.Y*d1.Y > 0 {
+				changed = true
+				continue
+			}
+			o = append(o, p)
+		}
+		r = o
+	}
+	return r
+}
+
+// assemble creates a MultiPolygon from open rings, where counterclockwise
+// rings are shells and clockwise rings are holes. Each hole is assigned to
+// the smallest shell that contains it.
+func assemble(rings [][]geom.Point) geom.MultiPolygon {
+	type shell struct {
+		poly   geom.Polygon
+		area   float64
+		bounds *geom.Bounds
+	}
+	var shells []shell
+	var holes []geom.Path
+	closed := func(r []geom.Point) geom.Path {
+		return append(geom.Path(r), r[0])
+	}
+	for _, r := range rings {
+		r = removeCollinear(r)
+		if len(r) < 3 {
+			continue
+		}
+		switch a := ringArea(r); {
+		case a > 0:
+			p := geom.Polygon{closed(r)}
+			shells = append(shells, shell{poly: p, area: a, bounds: p.Bounds()})
+		case a < 0:
+			holes = append(holes, closed(r))
+		}
+	}
+	for _, h := range holes {
+		hb := geom.Polygon{h}.Bounds()
+		best := -1
+		for i, s := range shells {
+			if !s.bounds.Overlaps(hb) || (best >= 0 && s.area >= shells[best].area) {
+				continue
+			}
+			if holeInside(h, s.poly[0]) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			shells[best].poly = append(shells[best].poly, h)
+		}
+	}
+	o := make(geom.MultiPolygon, len(shells))
+	for i, s := range shells {
+		o[i] = s.poly
+	}
+	return o
+}
+
+// holeInside returns whether hole h is inside shell s. Holes can touch
+// their shells, so it checks the first point of h that isn't on the edge
+// of s.
+func holeInside(h, s geom.Path) bool {
+	for _, p := range h {
+		if w := p.Within(geom.Polygon{s}); w != geom.OnEdge {
+			return w == geom.Inside
+		}
+	}
+	return false
+}
+
+// pinned returns whether p is at the center of a grid cell or at the
+// center of a square of four cells, where the rings of isobands can meet
+// the edge of the grid or each other.
+func (g *Grid) pinned(p geom.Point) bool {
+	i := math.Floor((p.X-g.W)/g.DX*2+0.5) / 2
+	j := math.Floor((p.Y-g.S)/g.DY*2+0.5) / 2
+	if i != math.Floor(i) && j != math.Floor(j) {
+		return p == geom.Point{X: g.W + i*g.DX, Y: g.S + j*g.DY}
+	}
+	if i == math.Floor(i) && j == math.Floor(j) {
+		return p == geom.Point{X: g.W + i*g.DX, Y: g.S + j*g.DY}
+	}
+	return false
+}
+
+// smoothPolygon smooths the rings of p without moving pinned vertices,
+// returning p unchanged if the smoothed polygon would not be valid.
+func (g *Grid) smoothPolygon(p geom.Polygon, iterations int) geom.Polygon {
+	o := make(geom.Polygon, len(p))
+	for i, r := range p {
+		r = r[:len(r)-1]
+		start := -1
+		for j, pt := range r {
+			if g.pinned(pt) {
+				start = j
+				break
+			}
+		}
+		if start < 0 {
+			// Rings without pinned vertices are smoothed as a whole.
+			o[i] = geom.Smooth(geom.Polygon{append(r, r[0])}, geom.Chaikin, iterations).(geom.Polygon)[0]
+			continue
+		}
+		// Otherwise, smooth the runs of vertices between pinned vertices.
+		r = append(append(append(geom.Path{}, r[start:]...), r[:start]...), r[start])
+		ring := geom.Path{r[0]}
+		run := geom.LineString{r[0]}
+		for _, pt := range r[1:] {
+			run = append(run, pt)
+			if g.pinned(pt) {
+				smoothed := geom.Smooth(run, geom.Chaikin, iterations).(geom.LineString)
+				ring = append(ring, smoothed[1:]...)
+				run = geom.LineString{pt}
+			}
+		}
+		o[i] = ring
+	}
+	if !ringsSimple(o) {
+		return p
+	}
+	for _, h := range o[1:] {
+		if !holeInside(h, o[0]) {
+			return p
+		}
+	}
+	return o
+}
+
+// ringsSimple returns whether the closed rings in rings neither cross
+// themselves nor each other. Rings are allowed to touch at vertices that
+// they share.
+func ringsSimple(rings []geom.Path) bool {
+	type ringSeg struct {
+		segment
+		minX, maxX float64
+	}
+	var segs []ringSeg
+	for _, r := range rings {
+		for i := 0; i < len(r)-1; i++ {
+			segs = append(segs, ringSeg{
+				segment: segment{r[i], r[i+1]},
+				minX:    math.Min(r[i].X, r[i+1].X),
+				maxX:    math.Max(r[i].X, r[i+1].X),
+			})
+		}
+	}
+	sort.Slice(segs, func(i, j int) bool { return segs[i].minX < segs[j].minX })
+	for i, s := range segs {
+		for j := i + 1; j < len(segs) && segs[j].minX <= s.maxX; j++ {
+			if segmentsCross(s.segment, segs[j].segment) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// orient returns a positive value if a, b and c are in counterclockwise
+// order, a negative value if they are in clockwise order, and zero if
+// they are collinear. The sign is exact.
+func orient(a, b, c geom.Point) float64 {
+	return robust.Orient2D(a.X, a.Y, b.X, b.Y, c.X, c.Y)
+}
+
+// opposite returns whether orientations a and b have opposite signs.
+func opposite(a, b float64) bool {
+	return a > 0 && b < 0 || a < 0 && b > 0
+}
+
+// segmentsCross returns whether s and t have any points in common other
+// than shared endpoints.
+func segmentsCross(s, t segment) bool {
+	o1, o2 := orient(s.a, s.b, t.a), orient(s.a, s.b, t.b)
+	o3, o4 := orient(t.a, t.b, s.a), orient(t.a, t.b, s.b)
+	if opposite(o1, o2) && opposite(o3, o4) {
+		return true
+	}
+	if o1 == 0 && o2 == 0 {
+		// The segments are collinear, so check whether they overlap.
+		d := sub(s.b, s.a)
+		proj := func(p geom.Point) float64 { return (p.X-s.a.X)*d.X + (p.Y-s.a.Y)*d.Y }
+		t0, t1 := proj(t.a), proj(t.b)
+		return math.Max(t0, t1) > 0 && math.Min(t0, t1) < d.X*d.X+d.Y*d.Y
+	}
+	// Check whether an endpoint of one segment touches the other segment
+	// anywhere but at a shared endpoint.
+	touches := func(p geom.Point, o float64, seg segment) bool {
+		if o != 0 || p == seg.a || p == seg.b {
+			return false
+		}
+		return math.Min(seg.a.X, seg.b.X) <= p.X && p.X <= math.Max(seg.a.X, seg.b.X) &&
+			math.Min(seg.a.Y, seg.b.Y) <= p.Y && p.Y <= math.Max(seg.a.Y, seg.b.Y)
+	}
+	return touches(t.a, o1, s) || touches(t.b, o2, s) || touches(s.a, o3, t) || touches(s.b, o4, t)
+}
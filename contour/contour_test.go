@@ -0,0 +1,158 @@
+package contour
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ctessum/geom"
+)
+
+// cone returns a grid whose values are the distance from the center of the
+// grid.
+func cone(n int) *Grid {
+	data := make([]float64, n*n)
+	c := float64(n) / 2
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			data[j*n+i] = math.Hypot(float64(i)+0.5-c, float64(j)+0.5-c)
+		}
+	}
+	return NewGrid(0, 0, 1, 1, n, n, data)
+}
+
+func TestIsolines(t *testing.T) {
+	g := cone(41)
+	for _, smooth := range []int{0, 3} {
+		lines := g.Isolines([]float64{5, 10, 100}, smooth)
+		if len(lines) != 3 {
+			t.Fatalf("have %d lines, want 3", len(lines))
+		}
+		for i, r := range []float64{5, 10} {
+			if len(lines[i]) != 1 {
+				t.Fatalf("smooth %d, %d: have %d lines, want 1", smooth, i, len(lines[i]))
+			}
+			l := lines[i][0]
+			if l[0] != l[len(l)-1] {
+				t.Errorf("smooth %d, %d: line is not closed", smooth, i)
+			}
+			if have, want := l.Length(), 2*math.Pi*r; math.Abs(have-want)/want > 0.01 {
+				t.Errorf("smooth %d, %d: have length %g, want %g", smooth, i, have, want)
+			}
+		}
+		if len(lines[2]) != 0 {
+			t.Errorf("smooth %d: have %v, want no lines", smooth, lines[2])
+		}
+	}
+}
+
+func TestIsobands(t *testing.T) {
+	g := cone(41)
+	bands := g.Isobands([]float64{math.Inf(-1), 5, 10, math.Inf(1)}, 0)
+	if len(bands) != 3 {
+		t.Fatalf("have %d bands, want 3", len(bands))
+	}
+	tests := []struct {
+		polygons, holes int
+		area            float64
+	}{
+		{polygons: 1, holes: 0, area: math.Pi * 25},
+		{polygons: 1, holes: 1, area: math.Pi * 75},
+		{polygons: 1, holes: 1, area: 40*40 - math.Pi*100},
+	}
+	var total float64
+	for i, test := range tests {
+		mp := bands[i]
+		if len(mp) != test.polygons {
+			t.Fatalf("%d: have %d polygons, want %d", i, len(mp), test.polygons)
+		}
+		if holes := len(mp[0]) - 1; holes != test.holes {
+			t.Errorf("%d: have %d holes, want %d", i, holes, test.holes)
+		}
+		area := mp.Area()
+		if math.Abs(area-test.area)/test.area > 0.01 {
+			t.Errorf("%d: have area %g, want %g", i, area, test.area)
+		}
+		total += area
+		checkValid(t, i, mp)
+	}
+	if want := 40. * 40; math.Abs(total-want) > 1e-9 {
+		t.Errorf("bands have total area %g, want %g", total, want)
+	}
+
+	smoothed := g.Isobands([]float64{math.Inf(-1), 5, 10, math.Inf(1)}, 2)
+	total = 0
+	for i, test := range tests {
+		total += smoothed[i].Area()
+		if area := smoothed[i].Area(); math.Abs(area-test.area)/test.area > 0.02 {
+			t.Errorf("smoothed %d: have area %g, want %g", i, area, test.area)
+		}
+		if smoothed[i].Len() <= bands[i].Len() {
+			t.Errorf("smoothed %d: polygon was not smoothed", i)
+		}
+		checkValid(t, i, smoothed[i])
+	}
+	if want := 40. * 40; math.Abs(total-want) > 1e-9 {
+		t.Errorf("smoothed bands have total area %g, want %g", total, want)
+	}
+}
+
+func TestIsobandsSaddle(t *testing.T) {
+	// The regions above and below the center value touch at the center of
+	// the grid.
+	g := NewGrid(10, 20, 1, 2, 2, 2, []float64{1, 0, 0, 1})
+	bands := g.Isobands([]float64{-1, 0.5, 2}, 0)
+	for i, mp := range bands {
+		if len(mp) != 2 {
+			t.Errorf("%d: have %d polygons, want 2: %v", i, len(mp), mp)
+		}
+		if area := mp.Area(); math.Abs(area-1) > 1e-12 {
+			t.Errorf("%d: have area %g, want 1", i, area)
+		}
+		checkValid(t, i, mp)
+	}
+	want := geom.Bounds{Min: geom.Point{X: 21, Y: 10.5}, Max: geom.Point{X: 23, Y: 11.5}}
+	if b := bands[1].Bounds(); *b != want {
+		t.Errorf("have bounds %v, want %v", *b, want)
+	}
+}
+
+func TestIsobandsMissing(t *testing.T) {
+	g := NewGrid(0, 0, 1, 1, 3, 3, []float64{
+		1, 1, 1,
+		1, 1, 1,
+		1, 1, math.NaN(),
+	})
+	bands := g.Isobands([]float64{0, 2}, 0)
+	if area := bands[0].Area(); area != 3 {
+		t.Errorf("have area %g, want 3", area)
+	}
+	want := geom.MultiPolygon{{{{X: 0.5, Y: 0.5}, {X: 2.5, Y: 0.5}, {X: 2.5, Y: 1.5}, {X: 1.5, Y: 1.5}, {X: 1.5, Y: 2.5}, {X: 0.5, Y: 2.5}, {X: 0.5, Y: 0.5}}}}
+	if !bands[0].Similar(want, 1e-12) {
+		t.Errorf("have %v, want %v", bands[0], want)
+	}
+}
+
+// checkValid checks that the shells of mp are counterclockwise, the holes
+// are clockwise and inside their shells, and no ring touches itself.
+func checkValid(t *testing.T, i int, mp geom.MultiPolygon) {
+	for _, p := range mp {
+		for j, r := range p {
+			a := ringArea(r[:len(r)-1])
+			if j == 0 && a <= 0 || j > 0 && a >= 0 {
+				t.Errorf("%d: ring %d has the wrong orientation", i, j)
+			}
+			seen := make(map[geom.Point]bool)
+			for _, pt := range r[:len(r)-1] {
+				if seen[pt] {
+					t.Errorf("%d: ring %d touches itself at %v", i, j, pt)
+				}
+				seen[pt] = true
+			}
+			if j > 0 {
+				if w := r[0].Within(geom.Polygon{p[0]}); w == geom.Outside {
+					t.Errorf("%d: hole %d is outside its shell", i, j)
+				}
+			}
+		}
+	}
+}
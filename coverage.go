@@ -0,0 +1,190 @@
+package geom
+
+// SimplifyCoverage simplifies a polygonal coverage (a set of polygons
+// that do not overlap but may share edges, such as census tracts) while
+// preserving the shared edges, so that no gaps or overlaps develop between
+// neighboring polygons.
+//
+// The rings of the polygons are split into arcs at the points where three or
+// more polygons meet or where a shared edge ends. Each distinct arc is
+// simplified once, using the same algorithm as Polygon.Simplify, while
+// ensuring that none of its shortened segments intersect the rest of the
+// arc or any of the other arcs. The polygons
+// are then rebuilt from the simplified arcs. Rings that collapse to fewer
+// than three distinct points are removed, and polygons whose outer ring
+// collapses are removed entirely.
+//
+// For the shared edges to be detected, neighboring polygons must have
+// identical vertices along their shared edges.
+//
+// The output has the same number of elements as polys. Elements are
+// Polygons where the input was a Polygon or *Bounds, and MultiPolygons
+// otherwise.
+func SimplifyCoverage(polys []Polygonal, tolerance float64) []Polygonal {
+	type ringRef struct {
+		arcs     []int
+		reversed []bool
+	}
+	type arcKey [2]Point
+
+	// Find the points where the neighbors of a vertex differ between
+	// the rings that contain it.
+	type neighbors [2]Point
+	seen := make(map[Point]neighbors)
+	junctions := make(map[Point]struct{})
+	rings := make([][][]Path, len(polys))
+	for i, pg := range polys {
+		for _, p := range pg.Polygons() {
+			var pRings []Path
+			for _, r := range p {
+				r = openRing(r)
+				if len(r) < 3 {
+					continue
+				}
+				pRings = append(pRings, r)
+				for k, pt := range r {
+					prev, next := r[(k+len(r)-1)%len(r)], r[(k+1)%len(r)]
+					if next.X < prev.X || next.X == prev.X && next.Y < prev.Y {
+						prev, next = next, prev
+					}
+					nb := neighbors{prev, next}
+					if s, ok := seen[pt]; !ok {
+						seen[pt] = nb
+					} else if s != nb {
+						junctions[pt] = struct{}{}
+					}
+				}
+			}
+			rings[i] = append(rings[i], pRings)
+		}
+	}
+
+	// Split the rings into unique arcs.
+	var arcs []Path
+	arcIndex := make(map[arcKey][]int)
+	addArc := func(a Path) (int, bool) {
+		key := arcKey{a[0], a[len(a)-1]}
+		for _, k := range []arcKey{key, {key[1], key[0]}} {
+			for _, i := range arcIndex[k] {
+				if sameLine(LineString(a), LineString(arcs[i])) {
+					return i, !a[0].Equals(arcs[i][0]) || (len(a) > 1 && !a[1].Equals(arcs[i][1]))
+				}
+			}
+		}
+		arcs = append(arcs, a)
+		arcIndex[key] = append(arcIndex[key], len(arcs)-1)
+		return len(arcs) - 1, false
+	}
+	refs := make([][][]ringRef, len(polys))
+	for i, pRings := range rings {
+		refs[i] = make([][]ringRef, len(pRings))
+		for j, pr := range pRings {
+			refs[i][j] = make([]ringRef, len(pr))
+			for k, r := range pr {
+				// Start the ring at a junction, or at a consistent
+				// point if it has no junctions.
+				start := -1
+				for m, pt := range r {
+					if _, ok := junctions[pt]; ok {
+						start = m
+						break
+					}
+				}
+				if start < 0 {
+					start = minPt(r)
+				}
+				var ref ringRef
+				arc := Path{r[start]}
+				for m := 1; m <= len(r); m++ {
+					pt := r[(start+m)%len(r)]
+					arc = append(arc, pt)
+					if _, ok := junctions[pt]; ok || m == len(r) {
+						a, rev := addArc(arc)
+						ref.arcs = append(ref.arcs, a)
+						ref.reversed = append(ref.reversed, rev)
+						arc = Path{pt}
+					}
+				}
+				refs[i][j][k] = ref
+			}
+		}
+	}
+
+	// Simplify each arc, making sure that each shortcut does not intersect
+	// the rest of the arc or the nearby arcs in their current state. The
+	// arcs meet each other at their endpoints, so segments that share an
+	// endpoint with a shortcut are skipped rather than ending the check.
+	bounds := make([]*Bounds, len(arcs))
+	for i, a := range arcs {
+		bounds[i] = LineString(a).Bounds()
+	}
+	for i, a := range arcs {
+		if len(a) < 3 {
+			continue
+		}
+		var others []Path
+		for j, b := range arcs {
+			if j != i && bounds[i].Overlaps(bounds[j]) {
+				others = append(others, b)
+			}
+		}
+		simplified := simplifyCurveChecked(a, others, tolerance, segmentCrossesPaths)
+		if a[0].Equals(a[len(a)-1]) && len(simplified) < 4 {
+			continue // Don't collapse rings made of a single arc.
+		}
+		arcs[i] = simplified
+	}
+
+	// Rebuild the polygons from the simplified arcs.
+	o := make([]Polygonal, len(polys))
+	for i, pRefs := range refs {
+		var mp MultiPolygon
+		for _, pr := range pRefs {
+			var p Polygon
+			for k, ref := range pr {
+				var r LineString
+				for m, a := range ref.arcs {
+					arc := arcs[a]
+					if ref.reversed[m] {
+						for n := len(arc) - 1; n >= 0; n-- {
+							r = appendNew(r, arc[n])
+						}
+					} else {
+						for _, pt := range arc {
+							r = appendNew(r, pt)
+						}
+					}
+				}
+				if len(r) < 4 {
+					if k == 0 {
+						break // The outer ring has collapsed.
+					}
+					continue
+				}
+				p = append(p, Path(r))
+			}
+			if len(p) > 0 {
+				mp = append(mp, p)
+			}
+		}
+		switch polys[i].(type) {
+		case Polygon, *Bounds:
+			if len(mp) > 0 {
+				o[i] = mp[0]
+			} else {
+				o[i] = Polygon{}
+			}
+		default:
+			o[i] = mp
+		}
+	}
+	return o
+}
+
+// openRing returns r without its closing point, if it has one.
+func openRing(r Path) Path {
+	if len(r) > 1 && r[0].Equals(r[len(r)-1]) {
+		return r[:len(r)-1]
+	}
+	return r
+}
@@ -0,0 +1,115 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimplifyCoverage(t *testing.T) {
+	// Two squares that share a wiggly edge, with a third polygon that
+	// forms an island in a hole in the first square.
+	shared := []Point{
+		{X: 2, Y: 0}, {X: 2.1, Y: 0.5}, {X: 1.9, Y: 1}, {X: 2.1, Y: 1.5},
+		{X: 1.9, Y: 2}, {X: 2.05, Y: 2.5}, {X: 2, Y: 3},
+	}
+	var left, right Path
+	left = append(left, Point{X: 0, Y: 0})
+	left = append(left, shared...)
+	left = append(left, Point{X: 0, Y: 3}, Point{X: 0, Y: 0})
+	right = append(right, Point{X: 4, Y: 3}, Point{X: 4, Y: 0})
+	right = append(right, shared...)
+	right = append(right, Point{X: 4, Y: 3})
+	hole := Path{{X: 0.5, Y: 0.5}, {X: 1, Y: 0.5}, {X: 1, Y: 1}, {X: 0.75, Y: 1.02}, {X: 0.5, Y: 1}, {X: 0.5, Y: 0.5}}
+	island := Path{{X: 0.5, Y: 1}, {X: 0.75, Y: 1.02}, {X: 1, Y: 1}, {X: 1, Y: 0.5}, {X: 0.5, Y: 0.5}, {X: 0.5, Y: 1}}
+	polys := []Polygonal{
+		Polygon{left, hole},
+		MultiPolygon{{right}},
+		Polygon{island},
+	}
+
+	var areaBefore float64
+	for _, p := range polys {
+		areaBefore += p.Area()
+	}
+	o := SimplifyCoverage(polys, 0.2)
+	if len(o) != 3 {
+		t.Fatalf("have %d polygons, want 3", len(o))
+	}
+	if _, ok := o[1].(MultiPolygon); !ok {
+		t.Errorf("type of polygon 1: have %T, want MultiPolygon", o[1])
+	}
+	var areaAfter float64
+	for _, p := range o {
+		areaAfter += p.Area()
+	}
+	// The polygons should still tile the same area with no gaps or overlaps.
+	if math.Abs(areaBefore-areaAfter) > 1.e-9 {
+		t.Errorf("total area: have %g, want %g", areaAfter, areaBefore)
+	}
+	if overlap := o[0].Intersection(o[1]); overlap != nil && overlap.Area() > 1.e-9 {
+		t.Errorf("polygons overlap: %v", overlap)
+	}
+	checkCoverage(t, o)
+	if n := o[0].Len() + o[1].Len(); n >= left.Len()+right.Len()+hole.Len() {
+		t.Errorf("polygons were not simplified: %v", o)
+	}
+	// The island should still fill the hole.
+	h := o[0].Polygons()[0][1]
+	if !sameLine(LineString(h), LineString(o[2].Polygons()[0][0])) {
+		t.Errorf("island %v does not match hole %v", o[2], h)
+	}
+	if len(h) != 5 {
+		t.Errorf("hole was not simplified: %v", h)
+	}
+}
+
+func TestSimplifyCoverageNoCrossings(t *testing.T) {
+	// The shared arc would be shortened to (0,0)-(4,0), which crosses the
+	// notch in the lower polygon that reaches up to (2,0.05).
+	upper := Polygon{{
+		{X: 0, Y: 0}, {X: 0, Y: 5}, {X: 10, Y: 5}, {X: 10, Y: 0}, {X: 6, Y: 0},
+		{X: 5, Y: 3}, {X: 4, Y: 0}, {X: 2, Y: 0.1}, {X: 0, Y: 0},
+	}}
+	lower := Polygon{{
+		{X: 0, Y: 0}, {X: 2, Y: 0.1}, {X: 4, Y: 0}, {X: 5, Y: 3}, {X: 6, Y: 0},
+		{X: 10, Y: 0}, {X: 10, Y: -2}, {X: 3, Y: -1}, {X: 2, Y: 0.05},
+		{X: 1, Y: -1}, {X: 0, Y: -2}, {X: 0, Y: 0},
+	}}
+	for i, polys := range [][]Polygonal{{upper, lower}, {lower, upper}} {
+		o := SimplifyCoverage(polys, 1)
+		checkCoverage(t, o)
+		if n := o[0].Len() + o[1].Len(); n >= upper.Len()+lower.Len() {
+			t.Errorf("%d: polygons were not simplified: %v", i, o)
+		}
+	}
+}
+
+// checkCoverage reports an error if any two segments of the rings of polys
+// cross, overlap or touch anywhere other than at their shared endpoints.
+// This means that no rings intersect and that every shared edge has the
+// same vertices in both of the polygons that share it.
+func checkCoverage(t *testing.T, polys []Polygonal) {
+	t.Helper()
+	var segs []segment
+	for _, pg := range polys {
+		for _, p := range pg.Polygons() {
+			for _, r := range p {
+				for i := 0; i < len(r)-1; i++ {
+					segs = append(segs, segment{r[i], r[i+1]})
+				}
+			}
+		}
+	}
+	for i, s1 := range segs {
+		for _, s2 := range segs[i+1:] {
+			n, p, _ := findIntersection(s1, s2)
+			switch {
+			case n == 0:
+			case s1 == s2 || s1.start == s2.end && s1.end == s2.start:
+			case n == 1 && (p == s1.start || p == s1.end) && (p == s2.start || p == s2.end):
+			default:
+				t.Errorf("segments %v and %v intersect in %v", s1, s2, polys)
+			}
+		}
+	}
+}
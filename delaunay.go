@@ -0,0 +1,177 @@
+package geom
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ctessum/geom/internal/robust"
+)
+
+// delaunay returns the Delaunay triangulation of pts, which must not
+// contain duplicate points, as triples of indices into pts in
+// counterclockwise order. It uses the Bowyer-Watson algorithm, which
+// adds the points one at a time and retriangulates the triangles whose
+// circumcircles contain each new point. The triangle that contains each
+// new point is found by walking from the triangles that were created for
+// the previous point, and the points are added in an order that keeps
+// consecutive points close together so that the walks are short.
+func delaunay(pts []Point) [][3]int {
+	if len(pts) < 3 {
+		return nil
+	}
+	b := NewBounds()
+	b.extendPoints(pts)
+	size := math.Max(b.Max.X-b.Min.X, b.Max.Y-b.Min.Y)
+	if size == 0 {
+		return nil
+	}
+	cx, cy := (b.Min.X+b.Max.X)/2, (b.Min.Y+b.Max.Y)/2
+
+	// Start with a triangle that contains all of the points.
+	all := append(append([]Point{}, pts...),
+		Point{X: cx - 20*size, Y: cy - 10*size},
+		Point{X: cx + 20*size, Y: cy - 10*size},
+		Point{X: cx, Y: cy + 20*size},
+	)
+	n := len(pts)
+	tr := &triangulation{
+		pts:  all,
+		tris: [][3]int{{n, n + 1, n + 2}},
+		adj:  [][3]int{{-1, -1, -1}},
+		dead: []bool{false},
+	}
+	for _, i := range spatialOrder(pts, b) {
+		tr.insert(i)
+	}
+
+	var o [][3]int
+	for t, tri := range tr.tris {
+		if !tr.dead[t] && tri[0] < n && tri[1] < n && tri[2] < n {
+			o = append(o, tri)
+		}
+	}
+	return o
+}
+
+// triangulation is a triangulation under construction. adj[t][k] is the
+// triangle on the other side of the edge from tris[t][k] to
+// tris[t][(k+1)%3], or -1 if there is none. Triangles that have been
+// replaced are marked as dead rather than removed.
+type triangulation struct {
+	pts  []Point
+	tris [][3]int
+	adj  [][3]int
+	dead []bool
+	last int // A triangle that is alive.
+}
+
+// insert adds point i to the triangulation.
+func (tr *triangulation) insert(i int) {
+	p := tr.pts[i]
+
+	// Find the cavity: the triangles whose circumcircles contain p, which
+	// are connected to the triangle that contains p.
+	start := tr.locate(p)
+	inCavity := map[int]bool{start: true}
+	cavity := []int{start}
+	for k := 0; k < len(cavity); k++ {
+		for _, nb := range tr.adj[cavity[k]] {
+			if nb < 0 || inCavity[nb] {
+				continue
+			}
+			t := tr.tris[nb]
+			a, b, c := tr.pts[t[0]], tr.pts[t[1]], tr.pts[t[2]]
+			if robust.InCircle(a.X, a.Y, b.X, b.Y, c.X, c.Y, p.X, p.Y) > 0 {
+				inCavity[nb] = true
+				cavity = append(cavity, nb)
+			}
+		}
+	}
+
+	// Connect p to the edges of the cavity. startsAt and endsAt hold the
+	// new triangles by the first and second vertices of their edges on the
+	// cavity boundary, which are used to link the new triangles together.
+	startsAt := make(map[int]int)
+	endsAt := make(map[int]int)
+	for _, t := range cavity {
+		tr.dead[t] = true
+		for k := 0; k < 3; k++ {
+			nb := tr.adj[t][k]
+			if nb >= 0 && inCavity[nb] {
+				continue
+			}
+			v0, v1 := tr.tris[t][k], tr.tris[t][(k+1)%3]
+			nt := len(tr.tris)
+			tr.tris = append(tr.tris, [3]int{v0, v1, i})
+			tr.adj = append(tr.adj, [3]int{nb, -1, -1})
+			tr.dead = append(tr.dead, false)
+			if nb >= 0 {
+				for kk := 0; kk < 3; kk++ {
+					if tr.adj[nb][kk] == t {
+						tr.adj[nb][kk] = nt
+					}
+				}
+			}
+			startsAt[v0] = nt
+			endsAt[v1] = nt
+		}
+	}
+	for v0, nt := range startsAt {
+		v1 := tr.tris[nt][1]
+		tr.adj[nt][1] = startsAt[v1]
+		tr.adj[nt][2] = endsAt[v0]
+	}
+	tr.last = len(tr.tris) - 1
+}
+
+// locate returns a triangle that contains p, by walking from tr.last
+// toward p.
+func (tr *triangulation) locate(p Point) int {
+	t := tr.last
+	for {
+		moved := false
+		for k := 0; k < 3; k++ {
+			a, b := tr.pts[tr.tris[t][k]], tr.pts[tr.tris[t][(k+1)%3]]
+			if nb := tr.adj[t][k]; nb >= 0 && orient(a, b, p) < 0 {
+				t = nb
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			return t
+		}
+	}
+}
+
+// spatialOrder returns the indices of pts ordered so that consecutive
+// points are close together, by dividing b into rows of cells and
+// visiting the cells in alternating directions along the rows.
+func spatialOrder(pts []Point, b *Bounds) []int {
+	cells := math.Max(1, math.Floor(math.Sqrt(float64(len(pts))/4)))
+	w := math.Max(b.Max.X-b.Min.X, b.Max.Y-b.Min.Y) / cells
+	cell := func(p Point) (row, col int) {
+		row = int(math.Min(cells-1, (p.Y-b.Min.Y)/w))
+		col = int(math.Min(cells-1, (p.X-b.Min.X)/w))
+		if row%2 == 1 {
+			col = -col
+		}
+		return row, col
+	}
+	o := make([]int, len(pts))
+	for i := range o {
+		o[i] = i
+	}
+	sort.Slice(o, func(i, j int) bool {
+		ri, ci := cell(pts[o[i]])
+		rj, cj := cell(pts[o[j]])
+		if ri != rj {
+			return ri < rj
+		}
+		if ci != cj {
+			return ci < cj
+		}
+		return o[i] < o[j]
+	})
+	return o
+}
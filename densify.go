@@ -0,0 +1,185 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ctessum/geom/proj"
+)
+
+// Densify returns a copy of g with extra vertices added so that no segment
+// is longer than maxSegmentLength. The extra vertices are evenly spaced
+// along each segment that is split. Points and MultiPoints are returned
+// unchanged, and *Bounds are returned as Polygons. Other Polygonal and
+// Linear types, such as FlatMultiPolygon, are returned as MultiPolygons
+// and MultiLineStrings. Z and M types keep their types, and the Z
+// coordinates and measures of the extra vertices are interpolated
+// linearly; segment lengths are measured in the X-Y plane.
+func Densify(g Geom, maxSegmentLength float64) Geom {
+	if !(maxSegmentLength > 0) {
+		panic(fmt.Errorf("geom: invalid maximum segment length %g", maxSegmentLength))
+	}
+	switch g := g.(type) {
+	case Point, MultiPoint:
+		return g
+	case LineString:
+		return LineString(densifyPath(Path(g), maxSegmentLength))
+	case MultiLineString:
+		o := make(MultiLineString, len(g))
+		for i, l := range g {
+			o[i] = LineString(densifyPath(Path(l), maxSegmentLength))
+		}
+		return o
+	case Polygon:
+		return densifyPolygon(g, maxSegmentLength)
+	case MultiPolygon:
+		o := make(MultiPolygon, len(g))
+		for i, p := range g {
+			o[i] = densifyPolygon(p, maxSegmentLength)
+		}
+		return o
+	case *Bounds:
+		return densifyPolygon(g.Polygons()[0], maxSegmentLength)
+	case GeometryCollection:
+		o := make(GeometryCollection, len(g))
+		for i, gg := range g {
+			o[i] = Densify(gg, maxSegmentLength)
+		}
+		return o
+	case PointZ, MultiPointZ:
+		return g
+	case LineStringZ:
+		return LineStringZ(densifyPathZ(PathZ(g), maxSegmentLength))
+	case MultiLineStringZ:
+		o := make(MultiLineStringZ, len(g))
+		for i, l := range g {
+			o[i] = LineStringZ(densifyPathZ(PathZ(l), maxSegmentLength))
+		}
+		return o
+	case PolygonZ:
+		return densifyPolygonZ(g, maxSegmentLength)
+	case MultiPolygonZ:
+		o := make(MultiPolygonZ, len(g))
+		for i, p := range g {
+			o[i] = densifyPolygonZ(p, maxSegmentLength)
+		}
+		return o
+	case LineStringM:
+		return densifyLineStringM(g, maxSegmentLength)
+	case MultiLineStringM:
+		o := make(MultiLineStringM, len(g))
+		for i, l := range g {
+			o[i] = densifyLineStringM(l, maxSegmentLength)
+		}
+		return o
+	case Polygonal:
+		return Densify(MultiPolygon(g.Polygons()), maxSegmentLength)
+	case Linear:
+		return Densify(linearLines(g), maxSegmentLength)
+	default:
+		panic(fmt.Errorf("geom: unsupported type %T", g))
+	}
+}
+
+// densifyPolygon densifies the rings of p, including the segment between
+// the last and first points of rings that are not explicitly closed.
+func densifyPolygon(p Polygon, maxSegmentLength float64) Polygon {
+	o := make(Polygon, len(p))
+	for i, r := range p {
+		closed := len(r) > 1 && r[0].Equals(r[len(r)-1])
+		if !closed && len(r) > 1 {
+			r = append(append(Path{}, r...), r[0])
+		}
+		o[i] = densifyPath(r, maxSegmentLength)
+		if !closed && len(r) > 1 {
+			o[i] = o[i][:len(o[i])-1]
+		}
+	}
+	return o
+}
+
+// densifyPath splits the segments of p that are longer than
+// maxSegmentLength into equal pieces.
+func densifyPath(p Path, maxSegmentLength float64) Path {
+	o := make(Path, 0, len(p))
+	for i, pt := range p {
+		if i > 0 {
+			n := int(math.Ceil(d(p[i-1], pt) / maxSegmentLength))
+			for k := 1; k < n; k++ {
+				o = append(o, interpolateSegment(p[i-1], pt, float64(k)/float64(n)))
+			}
+		}
+		o = append(o, pt)
+	}
+	return o
+}
+
+// densifyPolygonZ is the equivalent of densifyPolygon for PolygonZs.
+func densifyPolygonZ(p PolygonZ, maxSegmentLength float64) PolygonZ {
+	o := make(PolygonZ, len(p))
+	for i, r := range p {
+		closed := len(r) > 1 && r[0] == r[len(r)-1]
+		if !closed && len(r) > 1 {
+			r = append(append(PathZ{}, r...), r[0])
+		}
+		o[i] = densifyPathZ(r, maxSegmentLength)
+		if !closed && len(r) > 1 {
+			o[i] = o[i][:len(o[i])-1]
+		}
+	}
+	return o
+}
+
+// densifyPathZ is the equivalent of densifyPath for PathZs.
+func densifyPathZ(p PathZ, maxSegmentLength float64) PathZ {
+	o := make(PathZ, 0, len(p))
+	for i, pt := range p {
+		if i > 0 {
+			a := p[i-1]
+			n := int(math.Ceil(d(a.XY(), pt.XY()) / maxSegmentLength))
+			for k := 1; k < n; k++ {
+				f := float64(k) / float64(n)
+				o = append(o, PointZ{
+					X: a.X + f*(pt.X-a.X),
+					Y: a.Y + f*(pt.Y-a.Y),
+					Z: a.Z + f*(pt.Z-a.Z),
+				})
+			}
+		}
+		o = append(o, pt)
+	}
+	return o
+}
+
+// densifyLineStringM is the equivalent of densifyPath for LineStringMs.
+func densifyLineStringM(l LineStringM, maxSegmentLength float64) LineStringM {
+	o := make(LineStringM, 0, len(l))
+	for i, pt := range l {
+		if i > 0 {
+			n := int(math.Ceil(d(l[i-1].XY(), pt.XY()) / maxSegmentLength))
+			for k := 1; k < n; k++ {
+				o = append(o, interpolatePointM(l[i-1], pt, float64(k)/float64(n)))
+			}
+		}
+		o = append(o, pt)
+	}
+	return o
+}
+
+// TransformEnvelope returns the bounding box of b after it has been
+// transformed by t. Unlike Transform, which only transforms the corners of
+// b, the edges of b are densified so that no segment is longer than
+// maxSegmentLength before they are transformed, so that the result
+// includes the parts of the edges that curve outward in the new
+// projection. Extremes that occur in the interior of b, such as a pole
+// within b in a polar projection, are not accounted for.
+func (b *Bounds) TransformEnvelope(t proj.Transformer, maxSegmentLength float64) (*Bounds, error) {
+	if t == nil {
+		return b.Copy(), nil
+	}
+	p, err := Densify(b, maxSegmentLength).Transform(t)
+	if err != nil {
+		return nil, err
+	}
+	return p.Bounds(), nil
+}
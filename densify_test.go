@@ -0,0 +1,87 @@
+package geom
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDensify(t *testing.T) {
+	tests := []struct {
+		g, want Geom
+	}{
+		{g: Point{1, 2}, want: Point{1, 2}},
+		{
+			g:    LineString{{0, 0}, {3, 0}, {3, 0.5}},
+			want: LineString{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {3, 0.5}},
+		},
+		{
+			g:    MultiLineString{{{0, 0}, {0, 2}}},
+			want: MultiLineString{{{0, 0}, {0, 1}, {0, 2}}},
+		},
+		{
+			g:    Polygon{{{0, 0}, {2, 0}, {2, 1}, {0, 1}, {0, 0}}},
+			want: Polygon{{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+		{
+			// Unclosed rings are densified between the last and first points.
+			g:    MultiPolygon{{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}},
+			want: MultiPolygon{{{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}, {0, 1}}}},
+		},
+		{
+			g:    &Bounds{Min: Point{0, 0}, Max: Point{2, 2}},
+			want: Polygon{{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}, {0, 1}}},
+		},
+		{
+			g:    GeometryCollection{Point{1, 2}, LineString{{0, 0}, {2, 0}}},
+			want: GeometryCollection{Point{1, 2}, LineString{{0, 0}, {1, 0}, {2, 0}}},
+		},
+		{g: PointZ{1, 2, 3}, want: PointZ{1, 2, 3}},
+		{
+			// Segment lengths are measured in the X-Y plane.
+			g:    LineStringZ{{0, 0, 0}, {2, 0, 10}, {2, 0.5, 100}},
+			want: LineStringZ{{0, 0, 0}, {1, 0, 5}, {2, 0, 10}, {2, 0.5, 100}},
+		},
+		{
+			g:    MultiPolygonZ{{{{0, 0, 0}, {2, 0, 2}, {2, 1, 2}, {0, 1, 0}}}},
+			want: MultiPolygonZ{{{{0, 0, 0}, {1, 0, 1}, {2, 0, 2}, {2, 1, 2}, {1, 1, 1}, {0, 1, 0}}}},
+		},
+		{
+			g:    MultiLineStringM{{{0, 0, 10}, {0, 3, 40}}},
+			want: MultiLineStringM{{{0, 0, 10}, {0, 1, 20}, {0, 2, 30}, {0, 3, 40}}},
+		},
+	}
+	for i, test := range tests {
+		have := Densify(test.g, 1)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestBoundsTransformEnvelope(t *testing.T) {
+	// The bottom edge bulges downward in the middle.
+	tr := func(x, y float64) (float64, float64, error) {
+		return x, y - x*(10-x), nil
+	}
+	b := &Bounds{Min: Point{0, 0}, Max: Point{10, 1}}
+	have, err := b.TransformEnvelope(tr, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Bounds{Min: Point{0, -25}, Max: Point{10, 1}}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+	corners, _ := b.Transform(tr)
+	if cb := corners.Bounds(); cb.Min.Y != 0 {
+		t.Errorf("corner-only transform: have min y %g, want 0", cb.Min.Y)
+	}
+
+	errTr := func(x, y float64) (float64, float64, error) {
+		return 0, 0, fmt.Errorf("test error")
+	}
+	if _, err := b.TransformEnvelope(errTr, 1); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,145 @@
+// Package density estimates the density of point events on a regular grid
+// using kernel density estimation, for example to create heat maps.
+package density
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/ctessum/geom"
+	"github.com/ctessum/geom/contour"
+	"github.com/ctessum/geom/index/rtree"
+)
+
+// Kernel specifies the shape of the distribution that each point is spread
+// over.
+type Kernel int
+
+const (
+	// Gaussian spreads each point over a normal distribution with a
+	// standard deviation equal to the bandwidth. The distribution is
+	// truncated at four times the bandwidth.
+	Gaussian Kernel = iota
+
+	// Quartic, also called the biweight kernel, spreads each point over a
+	// smooth hump that falls to zero at a distance equal to the bandwidth.
+	Quartic
+
+	// Epanechnikov spreads each point over a parabolic hump that falls to
+	// zero at a distance equal to the bandwidth.
+	Epanechnikov
+)
+
+// radius returns the distance beyond which k is treated as zero, for
+// bandwidth h.
+func (k Kernel) radius(h float64) float64 {
+	if k == Gaussian {
+		return 4 * h
+	}
+	return h
+}
+
+// value returns the value of k at squared distance r2 from a point, for
+// bandwidth h. Each kernel integrates to one over the plane.
+func (k Kernel) value(r2, h float64) float64 {
+	h2 := h * h
+	switch k {
+	case Gaussian:
+		if r2 > 16*h2 {
+			return 0
+		}
+		return math.Exp(-r2/(2*h2)) / (2 * math.Pi * h2)
+	case Quartic:
+		if r2 >= h2 {
+			return 0
+		}
+		u := 1 - r2/h2
+		return 3 / (math.Pi * h2) * u * u
+	case Epanechnikov:
+		if r2 >= h2 {
+			return 0
+		}
+		return 2 / (math.Pi * h2) * (1 - r2/h2)
+	default:
+		panic(fmt.Errorf("density: invalid kernel %d", k))
+	}
+}
+
+// point is a weighted point stored in a spatial index.
+type point struct {
+	geom.Point
+	w float64
+}
+
+// Estimate returns the density of points, using kernel k with the given
+// bandwidth, on a grid of square cells with sides of length cellSize that
+// covers b. The density is evaluated at the center of each cell. If
+// weights is not nil, it holds a weight for each point; otherwise each
+// point has a weight of one. The density is in units of weight per unit
+// area, so it sums to approximately the total weight when multiplied by
+// the cell area, as long as the points are far enough inside b.
+//
+// The returned grid can be contoured or written out with WriteASCII. Its
+// first row is the southernmost one, so flipVertical must be true when
+// drawing it with carto.NewCanvasFromRaster.
+func Estimate(points geom.MultiPoint, weights []float64, k Kernel, bandwidth float64, b *geom.Bounds, cellSize float64) *contour.Grid {
+	if weights != nil && len(weights) != len(points) {
+		panic(fmt.Errorf("density: %d weights for %d points", len(weights), len(points)))
+	}
+	if !(bandwidth > 0) {
+		panic(fmt.Errorf("density: invalid bandwidth %g", bandwidth))
+	}
+	if !(cellSize > 0) {
+		panic(fmt.Errorf("density: invalid cell size %g", cellSize))
+	}
+	k.value(0, bandwidth) // Check that k is valid.
+	nx := int(math.Ceil((b.Max.X - b.Min.X) / cellSize))
+	ny := int(math.Ceil((b.Max.Y - b.Min.Y) / cellSize))
+	if nx < 1 || ny < 1 {
+		panic(fmt.Errorf("density: bounds %v are empty", b))
+	}
+
+	index := rtree.NewTree(25, 50)
+	for i, p := range points {
+		w := 1.
+		if weights != nil {
+			w = weights[i]
+		}
+		index.Insert(&point{Point: p, w: w})
+	}
+
+	data := make([]float64, nx*ny)
+	radius := k.radius(bandwidth)
+	nprocs := runtime.GOMAXPROCS(-1)
+	var wg sync.WaitGroup
+	wg.Add(nprocs)
+	for p := 0; p < nprocs; p++ {
+		go func(p int) {
+			defer wg.Done()
+			for j := p; j < ny; j += nprocs {
+				y := b.Min.Y + (float64(j)+0.5)*cellSize
+				// Find the points that are close enough to the row to
+				// affect it.
+				row := index.SearchIntersect(&geom.Bounds{
+					Min: geom.Point{X: b.Min.X - radius, Y: y - radius},
+					Max: geom.Point{X: b.Max.X + radius, Y: y + radius},
+				})
+				for _, g := range row {
+					pt := g.(*point)
+					dy := pt.Y - y
+					// Only visit the cells within radius of the point.
+					i0 := int(math.Max(0, math.Floor((pt.X-radius-b.Min.X)/cellSize-0.5)))
+					i1 := int(math.Min(float64(nx-1), math.Ceil((pt.X+radius-b.Min.X)/cellSize-0.5)))
+					for i := i0; i <= i1; i++ {
+						dx := pt.X - (b.Min.X + (float64(i)+0.5)*cellSize)
+						data[j*nx+i] += pt.w * k.value(dx*dx+dy*dy, bandwidth)
+					}
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+	return contour.NewGrid(b.Min.Y, b.Min.X, cellSize, cellSize, ny, nx, data)
+}
@@ -0,0 +1,101 @@
+package density
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ctessum/geom"
+	"github.com/ctessum/geom/contour"
+)
+
+func TestEstimate(t *testing.T) {
+	b := &geom.Bounds{Min: geom.Point{X: 0, Y: 0}, Max: geom.Point{X: 100, Y: 80}}
+	points := geom.MultiPoint{{X: 30, Y: 40}, {X: 60.3, Y: 45.7}, {X: 61, Y: 30}}
+	weights := []float64{1, 2, 0.5}
+	for _, k := range []Kernel{Gaussian, Quartic, Epanechnikov} {
+		g := Estimate(points, weights, k, 8, b, 0.5)
+		if g.NX != 200 || g.NY != 160 {
+			t.Errorf("%d: have grid size %d×%d, want 200×160", k, g.NX, g.NY)
+		}
+		var sum float64
+		for _, v := range g.Data {
+			sum += v * 0.5 * 0.5
+		}
+		if math.Abs(sum-3.5) > 0.01 {
+			t.Errorf("%d: total: have %g, want 3.5", k, sum)
+		}
+		// The density should be highest at the heaviest point.
+		max, maxI := 0., 0
+		for i, v := range g.Data {
+			if v > max {
+				max, maxI = v, i
+			}
+		}
+		p := geom.Point{X: (float64(maxI%g.NX) + 0.5) * 0.5, Y: (float64(maxI/g.NX) + 0.5) * 0.5}
+		if math.Hypot(p.X-60.3, p.Y-45.7) > 1 {
+			t.Errorf("%d: maximum at %v, want near (60.3, 45.7)", k, p)
+		}
+	}
+}
+
+func TestEstimate_brute(t *testing.T) {
+	b := &geom.Bounds{Min: geom.Point{X: -5, Y: -5}, Max: geom.Point{X: 5, Y: 5}}
+	points := geom.MultiPoint{{X: 0, Y: 0}, {X: 1.2, Y: -0.7}, {X: -3, Y: 4}, {X: 7, Y: 0}}
+	for _, k := range []Kernel{Gaussian, Quartic, Epanechnikov} {
+		g := Estimate(points, nil, k, 1.5, b, 0.25)
+		for j := 0; j < g.NY; j++ {
+			for i := 0; i < g.NX; i++ {
+				c := geom.Point{X: -5 + (float64(i)+0.5)*0.25, Y: -5 + (float64(j)+0.5)*0.25}
+				var want float64
+				for _, p := range points {
+					want += k.value((p.X-c.X)*(p.X-c.X)+(p.Y-c.Y)*(p.Y-c.Y), 1.5)
+				}
+				if have := g.Data[j*g.NX+i]; math.Abs(have-want) > 1e-12 {
+					t.Errorf("%d: (%d, %d): have %g, want %g", k, i, j, have, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEstimate_orientation(t *testing.T) {
+	// A single point in the northwest cell of a 2×2 grid.
+	b := &geom.Bounds{Min: geom.Point{X: 0, Y: 0}, Max: geom.Point{X: 10, Y: 10}}
+	g := Estimate(geom.MultiPoint{{X: 1, Y: 9}}, nil, Quartic, 3, b, 5)
+	for i, v := range g.Data {
+		// The first row is the southernmost one.
+		if (i == 2) != (v > 0) {
+			t.Errorf("%d: have %g", i, v)
+		}
+	}
+	var buf bytes.Buffer
+	if err := WriteASCII(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if north := lines[6]; strings.HasPrefix(north, "0 ") || !strings.HasSuffix(north, " 0") {
+		t.Errorf("first row: have %q, want the northwest cell to be nonzero", north)
+	}
+}
+
+func TestWriteASCII(t *testing.T) {
+	g := contour.NewGrid(10, 20, 2, 2, 2, 3, []float64{1, 2, 3, 4, math.NaN(), 6.5})
+	var b bytes.Buffer
+	if err := WriteASCII(&b, g); err != nil {
+		t.Fatal(err)
+	}
+	want := `ncols 3
+nrows 2
+xllcorner 20
+yllcorner 10
+cellsize 2
+NODATA_value -9999
+4 -9999 6.5
+1 2 3
+`
+	if have := b.String(); have != want {
+		t.Errorf("have\n%s\nwant\n%s", have, want)
+	}
+}
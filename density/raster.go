@@ -0,0 +1,46 @@
+package density
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ctessum/geom/contour"
+)
+
+// noData is the value written in place of missing values by WriteASCII.
+const noData = -9999
+
+// WriteASCII writes g to w in the Esri ASCII raster format, which can be
+// read by most GIS software. Rows are written from north to south, and
+// missing values are written as -9999. If the cells are not square, the
+// cell size is written using the dx and dy keywords supported by GDAL.
+func WriteASCII(w io.Writer, g *contour.Grid) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "ncols %d\nnrows %d\nxllcorner %s\nyllcorner %s\n",
+		g.NX, g.NY, formatFloat(g.W), formatFloat(g.S))
+	if g.DX == g.DY {
+		fmt.Fprintf(bw, "cellsize %s\n", formatFloat(g.DX))
+	} else {
+		fmt.Fprintf(bw, "dx %s\ndy %s\n", formatFloat(g.DX), formatFloat(g.DY))
+	}
+	fmt.Fprintf(bw, "NODATA_value %d\n", noData)
+	for j := g.NY - 1; j >= 0; j-- {
+		for i := 0; i < g.NX; i++ {
+			if i > 0 {
+				bw.WriteByte(' ')
+			}
+			v := g.Data[j*g.NX+i]
+			if math.IsNaN(v) {
+				v = noData
+			}
+			bw.WriteString(formatFloat(v))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func formatFloat(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
@@ -0,0 +1,161 @@
+package geom
+
+import "math"
+
+// Distance returns the shortest distance between a and b. Polygons include
+// their interiors, so the distance is zero if a is inside of b or b is
+// inside of a. It returns +Inf if either geometry is empty, like the
+// Distance methods of LineString and MultiLineString.
+func Distance(a, b Geom) float64 {
+	_, _, dist := nearestPoints(a, b, 0)
+	return dist
+}
+
+// NearestPoints returns the point pa on a and the point pb on b that are
+// closest to each other, so that Distance(a, b) is the distance from pa to
+// pb. If a and b intersect, pa and pb are the same point, which is shared
+// by both geometries. It returns NaN points if either geometry is empty.
+func NearestPoints(a, b Geom) (pa, pb Point) {
+	pa, pb, _ = nearestPoints(a, b, 0)
+	return pa, pb
+}
+
+// IsWithinDistance returns whether Distance(a, b) <= dist. It is faster
+// than calculating the distance, because it first checks whether the
+// bounding boxes of a and b are close enough and stops as soon as it finds
+// parts of a and b that are close enough. It returns false if either
+// geometry is empty.
+func IsWithinDistance(a, b Geom, dist float64) bool {
+	if boundsDistance(a.Bounds(), b.Bounds()) > dist {
+		return false
+	}
+	_, _, d := nearestPoints(a, b, dist)
+	return d <= dist && !math.IsInf(d, 1)
+}
+
+// boundsDistance returns the shortest distance between two bounding boxes.
+func boundsDistance(a, b *Bounds) float64 {
+	dx := math.Max(0, math.Max(b.Min.X-a.Max.X, a.Min.X-b.Max.X))
+	dy := math.Max(0, math.Max(b.Min.Y-a.Max.Y, a.Min.Y-b.Max.Y))
+	return math.Hypot(dx, dy)
+}
+
+// nearestPoints returns the points on a and b that are closest to each
+// other and the distance between them. It returns as soon as it finds
+// points that are no farther apart than stop, so the points may not be
+// the closest ones if stop > 0.
+func nearestPoints(a, b Geom, stop float64) (pa, pb Point, dist float64) {
+	pathsA, pathsB := nonEmptyPaths(geomPaths(a)), nonEmptyPaths(geomPaths(b))
+	if len(pathsA) == 0 || len(pathsB) == 0 {
+		return nanPoint, nanPoint, math.Inf(1)
+	}
+
+	// Where the edges of a and b cross, the distance is zero.
+	boundsB := make([]*Bounds, len(pathsB))
+	for i, path := range pathsB {
+		boundsB[i] = NewBounds()
+		boundsB[i].extendPoints(path)
+	}
+	for _, pathA := range pathsA {
+		for i := 0; i < len(pathA)-1; i++ {
+			segA := segment{pathA[i], pathA[i+1]}
+			segBounds := NewBounds().extendPoint(segA.start).extendPoint(segA.end)
+			for j, pathB := range pathsB {
+				if !segBounds.Overlaps(boundsB[j]) {
+					continue
+				}
+				for k := 0; k < len(pathB)-1; k++ {
+					if n, p, _ := findIntersection(segA, segment{pathB[k], pathB[k+1]}); n > 0 {
+						return p, p, 0
+					}
+				}
+			}
+		}
+	}
+
+	// Otherwise, parts of one geometry may be inside of polygons in the
+	// other.
+	for _, pg := range geomPolygonals(b) {
+		for _, path := range pathsA {
+			if pointInPolygonal(path[0], pg) != Outside {
+				return path[0], path[0], 0
+			}
+		}
+	}
+	for _, pg := range geomPolygonals(a) {
+		for _, path := range pathsB {
+			if pointInPolygonal(path[0], pg) != Outside {
+				return path[0], path[0], 0
+			}
+		}
+	}
+
+	// Otherwise, the shortest distance is from a vertex of one geometry
+	// to the other geometry.
+	boundsA := make([]*Bounds, len(pathsA))
+	for i, path := range pathsA {
+		boundsA[i] = NewBounds()
+		boundsA[i].extendPoints(path)
+	}
+	dist = math.Inf(1)
+	for _, p := range pathVertices(pathsA) {
+		if q, dd := nearestOnBoundedPaths(p, pathsB, boundsB, dist); dd < dist {
+			pa, pb, dist = p, q, dd
+			if dist <= stop {
+				return pa, pb, dist
+			}
+		}
+	}
+	for _, p := range pathVertices(pathsB) {
+		if q, dd := nearestOnBoundedPaths(p, pathsA, boundsA, dist); dd < dist {
+			pa, pb, dist = q, p, dd
+			if dist <= stop {
+				return pa, pb, dist
+			}
+		}
+	}
+	return pa, pb, dist
+}
+
+// nearestOnBoundedPaths is like nearestOnPaths, but it skips paths whose
+// bounds are farther than maxDist from p.
+func nearestOnBoundedPaths(p Point, paths []Path, bounds []*Bounds, maxDist float64) (Point, float64) {
+	pBounds := p.Bounds()
+	nearest, dist := nanPoint, math.Inf(1)
+	for i, path := range paths {
+		if boundsDistance(pBounds, bounds[i]) >= math.Min(dist, maxDist) {
+			continue
+		}
+		if q, dd := nearestOnPaths(p, []Path{path}); dd < dist {
+			nearest, dist = q, dd
+		}
+	}
+	return nearest, dist
+}
+
+// nonEmptyPaths returns the paths in paths that have at least one point.
+func nonEmptyPaths(paths []Path) []Path {
+	o := paths[:0:0]
+	for _, p := range paths {
+		if len(p) > 0 {
+			o = append(o, p)
+		}
+	}
+	return o
+}
+
+// geomPolygonals returns the polygonal parts of g.
+func geomPolygonals(g Geom) []Polygonal {
+	switch g := g.(type) {
+	case Polygonal:
+		return []Polygonal{g}
+	case GeometryCollection:
+		var o []Polygonal
+		for _, gg := range g {
+			o = append(o, geomPolygonals(gg)...)
+		}
+		return o
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	square := Polygon{
+		{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0}},
+		{{X: 1, Y: 1}, {X: 1, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 1}, {X: 1, Y: 1}},
+	}
+	tests := []struct {
+		a, b   Geom
+		dist   float64
+		pa, pb Point
+	}{
+		{
+			a: Point{X: 0, Y: 0}, b: Point{X: 3, Y: 4},
+			dist: 5, pa: Point{X: 0, Y: 0}, pb: Point{X: 3, Y: 4},
+		},
+		{
+			a: Point{X: 6, Y: 2}, b: square,
+			dist: 2, pa: Point{X: 6, Y: 2}, pb: Point{X: 4, Y: 2},
+		},
+		{ // Inside the polygon.
+			a: Point{X: 0.5, Y: 2}, b: square,
+			dist: 0, pa: Point{X: 0.5, Y: 2}, pb: Point{X: 0.5, Y: 2},
+		},
+		{ // Inside the hole.
+			a: square, b: Point{X: 2, Y: 2.5},
+			dist: 0.5, pa: Point{X: 2, Y: 3}, pb: Point{X: 2, Y: 2.5},
+		},
+		{ // Crossing lines.
+			a: LineString{{X: 0, Y: 0}, {X: 2, Y: 2}}, b: LineString{{X: 0, Y: 2}, {X: 2, Y: 0}},
+			dist: 0, pa: Point{X: 1, Y: 1}, pb: Point{X: 1, Y: 1},
+		},
+		{ // Parallel lines.
+			a: LineString{{X: 0, Y: 0}, {X: 2, Y: 0}}, b: MultiLineString{{{X: 5, Y: 1}, {X: 1, Y: 1}}},
+			dist: 1, pa: Point{X: 2, Y: 0}, pb: Point{X: 2, Y: 1},
+		},
+		{ // Line inside the polygon without touching its edges.
+			a: LineString{{X: 0.5, Y: 0.5}, {X: 3.5, Y: 0.5}}, b: square,
+			dist: 0, pa: Point{X: 0.5, Y: 0.5}, pb: Point{X: 0.5, Y: 0.5},
+		},
+		{ // Polygon inside the other's hole.
+			a: square, b: Polygon{{{X: 1.5, Y: 1.5}, {X: 2.5, Y: 1.5}, {X: 2.5, Y: 2.5}, {X: 1.5, Y: 2.5}}},
+			dist: 0.5, pa: Point{X: 1, Y: 1.5}, pb: Point{X: 1.5, Y: 1.5},
+		},
+		{
+			a: MultiPoint{{X: 10, Y: 10}, {X: 5, Y: 5}}, b: MultiPolygon{square},
+			dist: math.Sqrt2, pa: Point{X: 5, Y: 5}, pb: Point{X: 4, Y: 4},
+		},
+		{
+			a: GeometryCollection{Point{X: 20, Y: 0}, Point{X: 2, Y: 2}}, b: square,
+			dist: 1, pa: Point{X: 2, Y: 2}, pb: Point{X: 1, Y: 2},
+		},
+		{
+			a: MultiPoint{}, b: square,
+			dist: math.Inf(1), pa: nanPoint, pb: nanPoint,
+		},
+		{
+			a: Polygon{}, b: Point{X: 1, Y: 1},
+			dist: math.Inf(1), pa: nanPoint, pb: nanPoint,
+		},
+		{
+			a: LineStringZ{{X: 6, Y: 0, Z: 10}, {X: 6, Y: 4, Z: -10}}, b: square,
+			dist: 2, pa: Point{X: 6, Y: 0}, pb: Point{X: 4, Y: 0},
+		},
+		{
+			a: PolygonZ{{{X: 5, Y: 5, Z: 1}, {X: 6, Y: 5, Z: 1}, {X: 6, Y: 6, Z: 1}, {X: 5, Y: 6, Z: 1}}}, b: PointZ{X: 2, Y: 2, Z: 7},
+			dist: 3 * math.Sqrt2, pa: Point{X: 5, Y: 5}, pb: Point{X: 2, Y: 2},
+		},
+		{
+			a: LineStringM{{X: 0, Y: 6, M: 0}, {X: 4, Y: 6, M: 4}}, b: MultiLineStringM{{{X: 2, Y: 5, M: 1}, {X: 2, Y: 7, M: 3}}},
+			dist: 0, pa: Point{X: 2, Y: 6}, pb: Point{X: 2, Y: 6},
+		},
+	}
+	for i, test := range tests {
+		dist := Distance(test.a, test.b)
+		if !(math.Abs(dist-test.dist) < 1e-12 || dist == test.dist) {
+			t.Errorf("%d: distance: have %g, want %g", i, dist, test.dist)
+		}
+		pa, pb := NearestPoints(test.a, test.b)
+		if math.IsInf(test.dist, 1) {
+			if !math.IsNaN(pa.X) || !math.IsNaN(pb.X) {
+				t.Errorf("%d: points: have %v, %v, want NaN", i, pa, pb)
+			}
+		} else if !pointSimilar(pa, test.pa, 1e-12) || !pointSimilar(pb, test.pb, 1e-12) {
+			t.Errorf("%d: points: have %v, %v, want %v, %v", i, pa, pb, test.pa, test.pb)
+		}
+		if within := IsWithinDistance(test.a, test.b, test.dist+1e-9); within == math.IsInf(test.dist, 1) {
+			t.Errorf("%d: within %g: have %v", i, test.dist+1e-9, within)
+		}
+		if test.dist > 0 && IsWithinDistance(test.a, test.b, test.dist-1e-9) {
+			t.Errorf("%d: within %g: have true, want false", i, test.dist-1e-9)
+		}
+	}
+}
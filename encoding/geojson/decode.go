@@ -70,6 +70,28 @@ func makeLinearRing(coordinates [][]float64) geom.Path {
 	return points
 }
 
+func makeLinearRingZ(coordinates [][]float64) geom.PathZ {
+	points := make(geom.PathZ, len(coordinates))
+	for i, element := range coordinates {
+		if len(element) == 3 {
+			points[i].X = element[0]
+			points[i].Y = element[1]
+			points[i].Z = element[2]
+		} else {
+			panic(&InvalidGeometryError{})
+		}
+	}
+	return points
+}
+
+func makeLinearRingsZ(coordinates [][][]float64) []geom.PathZ {
+	pointss := make([]geom.PathZ, len(coordinates))
+	for i, element := range coordinates {
+		pointss[i] = makeLinearRingZ(element)
+	}
+	return pointss
+}
+
 func makeLinearRings(coordinates [][][]float64) []geom.Path {
 	pointss := make([]geom.Path, len(coordinates))
 	for i, element := range coordinates {
@@ -85,6 +107,8 @@ func doFromGeoJSON(g *Geometry) geom.Geom {
 		switch len(coordinates) {
 		case 2:
 			return geom.Point{coordinates[0], coordinates[1]}
+		case 3:
+			return geom.PointZ{coordinates[0], coordinates[1], coordinates[2]}
 		default:
 			panic(&InvalidGeometryError{})
 		}
@@ -96,6 +120,8 @@ func doFromGeoJSON(g *Geometry) geom.Geom {
 		switch len(coordinates[0]) {
 		case 2:
 			return geom.MultiPoint(makeLinearRing(coordinates))
+		case 3:
+			return geom.MultiPointZ(makeLinearRingZ(coordinates))
 		default:
 			panic(&InvalidGeometryError{})
 		}
@@ -107,6 +133,8 @@ func doFromGeoJSON(g *Geometry) geom.Geom {
 		switch len(coordinates[0]) {
 		case 2:
 			return geom.LineString(makeLinearRing(coordinates))
+		case 3:
+			return geom.LineStringZ(makeLinearRingZ(coordinates))
 		default:
 			panic(&InvalidGeometryError{})
 		}
@@ -122,6 +150,12 @@ func doFromGeoJSON(g *Geometry) geom.Geom {
 				multiLineString[i] = geom.LineString(makeLinearRing(coord))
 			}
 			return multiLineString
+		case 3:
+			multiLineString := make(geom.MultiLineStringZ, len(coordinates))
+			for i, coord := range coordinates {
+				multiLineString[i] = geom.LineStringZ(makeLinearRingZ(coord))
+			}
+			return multiLineString
 		default:
 			panic(&InvalidGeometryError{})
 		}
@@ -133,6 +167,8 @@ func doFromGeoJSON(g *Geometry) geom.Geom {
 		switch len(coordinates[0][0]) {
 		case 2:
 			return geom.Polygon(makeLinearRings(coordinates))
+		case 3:
+			return geom.PolygonZ(makeLinearRingsZ(coordinates))
 		default:
 			panic(&InvalidGeometryError{})
 		}
@@ -148,6 +184,12 @@ func doFromGeoJSON(g *Geometry) geom.Geom {
 				multiPolygon[i] = makeLinearRings(coord)
 			}
 			return multiPolygon
+		case 3:
+			multiPolygon := make(geom.MultiPolygonZ, len(coordinates))
+			for i, coord := range coordinates {
+				multiPolygon[i] = makeLinearRingsZ(coord)
+			}
+			return multiPolygon
 		default:
 			panic(&InvalidGeometryError{})
 		}
@@ -35,6 +35,26 @@ func pointsssCoordinates(pointsss [][]geom.Path) [][][][]float64 {
 	return coordinates
 }
 
+func pointZCoordinates(point geom.PointZ) []float64 {
+	return []float64{point.X, point.Y, point.Z}
+}
+
+func pointsZCoordinates(points []geom.PointZ) [][]float64 {
+	coordinates := make([][]float64, len(points))
+	for i, point := range points {
+		coordinates[i] = pointZCoordinates(point)
+	}
+	return coordinates
+}
+
+func pointssZCoordinates(pointss []geom.PathZ) [][][]float64 {
+	coordinates := make([][][]float64, len(pointss))
+	for i, points := range pointss {
+		coordinates[i] = pointsZCoordinates(points)
+	}
+	return coordinates
+}
+
 func ToGeoJSON(g geom.Geom) (*Geometry, error) {
 	switch g.(type) {
 	case geom.Point:
@@ -77,6 +97,50 @@ func ToGeoJSON(g geom.Geom) (*Geometry, error) {
 			Type:        "MultiPolygon",
 			Coordinates: pointsssCoordinates(pathsList),
 		}, nil
+	case geom.PointZ:
+		return &Geometry{
+			Type:        "Point",
+			Coordinates: pointZCoordinates(g.(geom.PointZ)),
+		}, nil
+	case geom.MultiPointZ:
+		return &Geometry{
+			Type:        "MultiPoint",
+			Coordinates: pointsZCoordinates(g.(geom.MultiPointZ)),
+		}, nil
+	case geom.LineStringZ:
+		return &Geometry{
+			Type:        "LineString",
+			Coordinates: pointsZCoordinates(g.(geom.LineStringZ)),
+		}, nil
+	case geom.MultiLineStringZ:
+		lines := g.(geom.MultiLineStringZ)
+		coordinates := make([][][]float64, len(lines))
+		for i, line := range lines {
+			coordinates[i] = pointsZCoordinates(line)
+		}
+		return &Geometry{
+			Type:        "MultiLineString",
+			Coordinates: coordinates,
+		}, nil
+	case geom.PolygonZ:
+		return &Geometry{
+			Type:        "Polygon",
+			Coordinates: pointssZCoordinates(g.(geom.PolygonZ)),
+		}, nil
+	case geom.MultiPolygonZ:
+		polys := g.(geom.MultiPolygonZ)
+		coordinates := make([][][][]float64, len(polys))
+		for i, poly := range polys {
+			coordinates[i] = pointssZCoordinates(poly)
+		}
+		return &Geometry{
+			Type:        "MultiPolygon",
+			Coordinates: coordinates,
+		}, nil
+	case geom.FlatMultiLineString:
+		return ToGeoJSON(g.(geom.FlatMultiLineString).MultiLineString())
+	case geom.FlatMultiPolygon:
+		return ToGeoJSON(g.(geom.FlatMultiPolygon).MultiPolygon())
 	default:
 		return nil, &UnsupportedGeometryError{reflect.TypeOf(g).String()}
 	}
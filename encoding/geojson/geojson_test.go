@@ -45,6 +45,30 @@ func TestGeoJSON(t *testing.T) {
 			},
 			[]byte(`{"type":"MultiPolygon","coordinates":[[[[1,2],[3,4],[5,6]]],[[[7,8],[9,10],[11,12]]]]}`),
 		},
+		{
+			geom.PointZ{1, 2, 3},
+			[]byte(`{"type":"Point","coordinates":[1,2,3]}`),
+		},
+		{
+			geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}},
+			[]byte(`{"type":"MultiPoint","coordinates":[[1,2,3],[4,5,6]]}`),
+		},
+		{
+			geom.LineStringZ{{1, 2, 3}, {4, 5, 6}},
+			[]byte(`{"type":"LineString","coordinates":[[1,2,3],[4,5,6]]}`),
+		},
+		{
+			geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}, {{7, 8, 9}, {10, 11, 12}}},
+			[]byte(`{"type":"MultiLineString","coordinates":[[[1,2,3],[4,5,6]],[[7,8,9],[10,11,12]]]}`),
+		},
+		{
+			geom.PolygonZ{{{1, 2, 0}, {3, 4, 1}, {5, 6, 2}}},
+			[]byte(`{"type":"Polygon","coordinates":[[[1,2,0],[3,4,1],[5,6,2]]]}`),
+		},
+		{
+			geom.MultiPolygonZ{{{{1, 2, 0}, {3, 4, 1}, {5, 6, 2}}}},
+			[]byte(`{"type":"MultiPolygon","coordinates":[[[[1,2,0],[3,4,1],[5,6,2]]]]}`),
+		},
 	}
 	for _, tc := range testCases {
 		if got, err := Encode(tc.g); err != nil || !reflect.DeepEqual(got, tc.geoJSON) {
@@ -74,6 +98,7 @@ func TestGeoJSONDecode(t *testing.T) {
 		[]byte(`{"coordinates":[[1,2],[3,4,5]],"type":"LineString"}`),
 		[]byte(`{"coordinates":[""],"type":"LineString"}`),
 		[]byte(`{"coordinates":[[1,2,3,4],[5,6,7,8]],"type":"LineString"}`),
+		[]byte(`{"coordinates":[[1,2,3],[4,5]],"type":"LineString"}`),
 		[]byte(`{"type":"MultiLineString"}`),
 		[]byte(`{"coordinates":[[1,2,3,4],[5,6,7,8]],"type":"MultiLineString"}`),
 		[]byte(`{"type":"Polygon"}`),
@@ -90,3 +115,24 @@ func TestGeoJSONDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestGeoJSONFlat(t *testing.T) {
+	testCases := []struct {
+		g       geom.Geom
+		geoJSON []byte
+	}{
+		{
+			geom.NewFlatMultiLineString(geom.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}}),
+			[]byte(`{"type":"MultiLineString","coordinates":[[[1,2],[3,4]],[[5,6],[7,8]]]}`),
+		},
+		{
+			geom.NewFlatMultiPolygon(geom.Polygon{{{1, 2}, {3, 4}, {5, 6}}}),
+			[]byte(`{"type":"MultiPolygon","coordinates":[[[[1,2],[3,4],[5,6]]]]}`),
+		},
+	}
+	for _, tc := range testCases {
+		if got, err := Encode(tc.g); err != nil || !reflect.DeepEqual(got, tc.geoJSON) {
+			t.Errorf("Encode(%#v) == %s, %v, want %s, nil", tc.g, got, err, tc.geoJSON)
+		}
+	}
+}
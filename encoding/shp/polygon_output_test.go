@@ -127,3 +127,68 @@ func TestEncoder_bounds(t *testing.T) {
 	os.Remove(testFile + ".dbf")
 
 }
+
+func TestEncoder_polyLineM(t *testing.T) {
+
+	const testFile = "testdata/test_output_m"
+
+	type route struct {
+		geom.MultiLineStringM
+		Name string
+	}
+	type route2D struct {
+		geom.MultiLineString
+	}
+
+	r := route{
+		MultiLineStringM: geom.MultiLineStringM{
+			{{X: 0, Y: 0, M: 0}, {X: 3, Y: 4, M: 5}},
+			{{X: 10, Y: 0, M: 5}, {X: 10, Y: 10, M: 15}},
+		},
+		Name: "a",
+	}
+
+	shape, err := NewEncoder(testFile+".shp", route{})
+	if err != nil {
+		t.Fatalf("error creating output shapefile: %v", err)
+	}
+	if err = shape.Encode(r); err != nil {
+		t.Fatalf("error writing output shapefile: %v", err)
+	}
+	shape.Close()
+	defer func() {
+		os.Remove(testFile + ".shp")
+		os.Remove(testFile + ".shx")
+		os.Remove(testFile + ".dbf")
+	}()
+
+	d, err := NewDecoder(testFile + ".shp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r2 route
+	d.DecodeRow(&r2)
+	if err := d.Error(); err != nil {
+		t.Fatalf("error decoding shapefile: %v", err)
+	}
+	d.Close()
+	if !reflect.DeepEqual(r2.MultiLineStringM, r.MultiLineStringM) {
+		t.Errorf("%+v != %+v", r2.MultiLineStringM, r.MultiLineStringM)
+	}
+
+	// Measures are dropped when decoding into a field without them.
+	d, err = NewDecoder(testFile + ".shp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r3 route2D
+	d.DecodeRow(&r3)
+	if err := d.Error(); err != nil {
+		t.Fatalf("error decoding shapefile: %v", err)
+	}
+	d.Close()
+	want := r.MultiLineStringM.Force2D()
+	if !reflect.DeepEqual(r3.MultiLineString, want) {
+		t.Errorf("%v != %v", r3.MultiLineString, want)
+	}
+}
@@ -1,6 +1,7 @@
 // Package shp decodes and encodes shapefiles to and from
-// geometry objects. Z and M data in the shapefile geometry
-// is ignored.
+// geometry objects. Z data in the shapefile geometry
+// is ignored, and so is M data unless it is requested
+// (see Decoder.KeepM).
 package shp
 
 import (
@@ -38,6 +39,15 @@ const (
 // reader.
 type Decoder struct {
 	shp.Reader
+
+	// KeepM specifies whether the measures (M values) in PolyLineM
+	// shapes should be kept, in which case the shapes are decoded as
+	// geom.MultiLineStringM. By default, the measures are dropped and the
+	// shapes are decoded as geom.MultiLineString. DecodeRow also keeps
+	// the measures when the geometry field has a measured type such as
+	// geom.MultiLineStringM.
+	KeepM bool
+
 	row          int
 	fieldIndices map[string]int
 	err          error
@@ -123,6 +133,12 @@ func (r *Decoder) DecodeRow(rec interface{}) bool {
 				continue
 			}
 
+			if (!r.KeepM && fType.Type.Kind() == reflect.Interface) ||
+				!reflect.TypeOf(g).AssignableTo(fType.Type) {
+				// Drop the M values unless they were requested and the
+				// field can hold them.
+				g = force2D(g)
+			}
 			fValue.Set(reflect.ValueOf(g))
 
 			// Then, check the tag name
@@ -166,6 +182,9 @@ func (r *Decoder) DecodeRowFields(fieldNames ...string) (
 		r.err = err
 		return
 	}
+	if !r.KeepM {
+		g = force2D(g)
+	}
 
 	// Get fields
 	for _, name := range fieldNames {
@@ -185,6 +204,14 @@ func (r *Decoder) DecodeRowFields(fieldNames ...string) (
 
 }
 
+// force2D returns g without its M values, if it has any.
+func force2D(g geom.Geom) geom.Geom {
+	if f, ok := g.(interface{ Force2D() geom.Geom }); ok {
+		return f.Force2D()
+	}
+	return g
+}
+
 // Error returns any errors that have been encountered while decoding
 // a shapfile.
 func (r Decoder) Error() error {
@@ -340,6 +367,9 @@ func NewEncoder(filename string, archetype interface{}) (*Encoder, error) {
 			case "LineStringM":
 				shpType = shp.POLYLINEM
 				e.geomIndex = i
+			case "MultiLineStringM":
+				shpType = shp.POLYLINEM
+				e.geomIndex = i
 			case "PolygonM":
 				shpType = shp.POLYGONM
 				e.geomIndex = i
@@ -136,16 +136,16 @@ func polyLine2geom(s shp.PolyLine) geom.Geom {
 	return pl
 }
 func polyLineM2geom(s shp.PolyLineM) geom.Geom {
-	var pl geom.MultiLineString = make([]geom.LineString, len(s.Parts))
-	jj := 0
+	var pl geom.MultiLineStringM = make([]geom.LineStringM, len(s.Parts))
 	for i := 0; i < len(s.Parts); i++ {
 		start, end := getStartEnd(s.Parts, s.Points, i)
-		pl[i] = make([]geom.Point, end-start)
+		pl[i] = make([]geom.PointM, end-start)
 		for j := start; j < end; j++ {
 			ss := s.Points[j]
-			pl[i][j-start] =
-				geom.Point{ss.X, ss.Y} //, s.MArray[jj]}
-			jj++
+			pl[i][j-start] = geom.PointM{X: ss.X, Y: ss.Y}
+			if j < len(s.MArray) {
+				pl[i][j-start].M = s.MArray[j]
+			}
 		}
 	}
 	return pl
@@ -203,6 +203,10 @@ func geom2Shp(g geom.Geom) (shp.Shape, error) {
 		return geom2polyLine(geom.MultiLineString{g.(geom.LineString)}), nil
 	case geom.MultiLineString:
 		return geom2polyLine(g.(geom.MultiLineString)), nil
+	case geom.LineStringM:
+		return geom2polyLineM(geom.MultiLineStringM{g.(geom.LineStringM)}), nil
+	case geom.MultiLineStringM:
+		return geom2polyLineM(g.(geom.MultiLineStringM)), nil
 	//case t == "MultiPatch": // not yet supported
 	case geom.MultiPoint:
 		return geom2multiPoint(g.(geom.MultiPoint)), nil
@@ -255,6 +259,27 @@ func geom2polyLine(g geom.MultiLineString) shp.Shape {
 	}
 	return shp.NewPolyLine(parts)
 }
+func geom2polyLineM(g geom.MultiLineStringM) shp.Shape {
+	parts := make([][]shp.Point, len(g))
+	var m []float64
+	for i, l := range g {
+		parts[i] = make([]shp.Point, len(l))
+		for j, p := range l {
+			parts[i][j] = shp.Point{X: p.X, Y: p.Y}
+			m = append(m, p.M)
+		}
+	}
+	pl := shp.NewPolyLine(parts)
+	return &shp.PolyLineM{
+		Box:       pl.Box,
+		NumParts:  pl.NumParts,
+		NumPoints: pl.NumPoints,
+		Parts:     pl.Parts,
+		Points:    pl.Points,
+		MRange:    valrange(m),
+		MArray:    m,
+	}
+}
 func geom2multiPoint(g geom.MultiPoint) shp.Shape {
 	mp := new(shp.MultiPoint)
 	mp.Box = bounds2box(g)
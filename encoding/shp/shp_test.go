@@ -3,6 +3,8 @@ package shp
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"testing"
 
 	"github.com/ctessum/geom"
 )
@@ -79,3 +81,67 @@ func Example() {
 	// polygon area 2.3, value 6
 	// polygon area 2.17, value 1
 }
+
+func TestDecoder_polyLineM(t *testing.T) {
+	const testFile = "testdata/polylinem.shp"
+	want := []geom.MultiLineString{
+		{{{X: 0, Y: 0}, {X: 3, Y: 4}}, {{X: 10, Y: 0}, {X: 10, Y: 10}}},
+		{{{X: 0, Y: 10}, {X: 5, Y: 10}}},
+	}
+
+	// PolyLineM shapes are decoded without their measures by default.
+	d, err := NewDecoder(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		var rec struct {
+			geom.Geom
+			Name string
+		}
+		if !d.DecodeRow(&rec) {
+			break
+		}
+		if !reflect.DeepEqual(rec.Geom, want[i]) {
+			t.Errorf("DecodeRow %d: have %#v, want %#v", i, rec.Geom, want[i])
+		}
+	}
+	if err := d.Error(); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	d, err = NewDecoder(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		g, _, more := d.DecodeRowFields("Name")
+		if !more {
+			break
+		}
+		if !reflect.DeepEqual(g, want[i]) {
+			t.Errorf("DecodeRowFields %d: have %#v, want %#v", i, g, want[i])
+		}
+	}
+	if err := d.Error(); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	// The measures are kept when requested.
+	d, err = NewDecoder(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.KeepM = true
+	g, _, _ := d.DecodeRowFields()
+	wantM := geom.MultiLineStringM{
+		{{X: 0, Y: 0, M: 0}, {X: 3, Y: 4, M: 5}},
+		{{X: 10, Y: 0, M: 5}, {X: 10, Y: 10, M: 15}},
+	}
+	if !reflect.DeepEqual(g, wantM) {
+		t.Errorf("KeepM: have %#v, want %#v", g, wantM)
+	}
+	d.Close()
+}
@@ -0,0 +1,233 @@
+package wkb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ctessum/geom"
+)
+
+// DecodeFlat decodes a Polygon or MultiPolygon from buf as a
+// geom.FlatMultiPolygon, or a LineString or MultiLineString as a
+// geom.FlatMultiLineString.
+//
+// The coordinates are decoded from buf directly into the Coords slice of
+// the result, without creating any intermediate geometries or buffers.
+// buf is read twice: first to find the number of coordinates, rings and
+// parts, so that each slice of the result is allocated once at its final
+// size, and then to fill the slices in. The result does not refer to buf,
+// so buf can be reused.
+func DecodeFlat(buf []byte) (geom.Geom, error) {
+	sizes := &flatDecoder{buf: buf, sizing: true}
+	sizes.decode()
+	if err := sizes.finish(); err != nil {
+		return nil, err
+	}
+	d := &flatDecoder{
+		buf:    buf,
+		coords: make([]float64, 0, sizes.nCoords),
+		ends:   make([]int, 0, sizes.nEnds),
+		parts:  make([]int, 0, sizes.nParts),
+	}
+	g := d.decode()
+	return g, d.finish()
+}
+
+// flatDecoder reads coordinates from a WKB buffer. After an error, its
+// methods do nothing.
+type flatDecoder struct {
+	buf []byte
+	pos int
+
+	// sizing is true when the decoder only counts the coordinates, ring
+	// or line ends and polygon ends in buf, in nCoords, nEnds and
+	// nParts, rather than reading them into coords, ends and parts.
+	sizing                 bool
+	nCoords, nEnds, nParts int
+
+	coords []float64
+	ends   []int
+	parts  []int
+	err    error
+}
+
+// decode reads a flat geometry from buf.
+func (d *flatDecoder) decode() geom.Geom {
+	t, byteOrder := d.header()
+	switch t {
+	case wkbPolygon:
+		d.polygon(byteOrder)
+		return geom.FlatMultiPolygon{Coords: d.coords, RingEnds: d.ends, PolygonEnds: d.parts}
+	case wkbMultiPolygon:
+		n := d.uint32(byteOrder)
+		for i := uint32(0); i < n && d.err == nil; i++ {
+			if t, byteOrder := d.header(); t != wkbPolygon && d.err == nil {
+				d.err = fmt.Errorf("wkb: unexpected geometry type %d in MultiPolygon", t)
+			} else {
+				d.polygon(byteOrder)
+			}
+		}
+		return geom.FlatMultiPolygon{Coords: d.coords, RingEnds: d.ends, PolygonEnds: d.parts}
+	case wkbLineString:
+		d.points(byteOrder)
+		return geom.FlatMultiLineString{Coords: d.coords, Ends: d.ends}
+	case wkbMultiLineString:
+		n := d.uint32(byteOrder)
+		for i := uint32(0); i < n && d.err == nil; i++ {
+			if t, byteOrder := d.header(); t != wkbLineString && d.err == nil {
+				d.err = fmt.Errorf("wkb: unexpected geometry type %d in MultiLineString", t)
+			} else {
+				d.points(byteOrder)
+			}
+		}
+		return geom.FlatMultiLineString{Coords: d.coords, Ends: d.ends}
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("wkb: unsupported geometry type %d for flat decoding", t)
+		}
+		return nil
+	}
+}
+
+// need checks that n more bytes are available.
+func (d *flatDecoder) need(n int) bool {
+	if d.err == nil && len(d.buf)-d.pos < n {
+		d.err = io.ErrUnexpectedEOF
+	}
+	return d.err == nil
+}
+
+// header reads the byte order and geometry type of a geometry.
+func (d *flatDecoder) header() (uint32, binary.ByteOrder) {
+	if !d.need(1) {
+		return 0, nil
+	}
+	var byteOrder binary.ByteOrder
+	switch d.buf[d.pos] {
+	case wkbXDR:
+		byteOrder = binary.BigEndian
+	case wkbNDR:
+		byteOrder = binary.LittleEndian
+	default:
+		d.err = fmt.Errorf("invalid byte order %v", d.buf[d.pos])
+		return 0, nil
+	}
+	d.pos++
+	t := d.uint32(byteOrder)
+	if t&ewkbSRID != 0 {
+		d.uint32(byteOrder)
+	}
+	if t&(ewkbZ|ewkbM) != 0 {
+		d.err = fmt.Errorf("wkb: flat decoding doesn't support Z or M coordinates")
+	}
+	return t &^ ewkbSRID, byteOrder
+}
+
+func (d *flatDecoder) uint32(byteOrder binary.ByteOrder) uint32 {
+	if !d.need(4) {
+		return 0
+	}
+	v := byteOrder.Uint32(d.buf[d.pos:])
+	d.pos += 4
+	return v
+}
+
+// points reads a list of points into d.coords, and appends the end of the
+// list to d.ends.
+func (d *flatDecoder) points(byteOrder binary.ByteOrder) {
+	n := int(d.uint32(byteOrder))
+	if !d.need(16 * n) {
+		return
+	}
+	if d.sizing {
+		d.pos += 16 * n
+		d.nCoords += 2 * n
+		d.nEnds++
+		return
+	}
+	for i := 0; i < 2*n; i++ {
+		d.coords = append(d.coords, math.Float64frombits(byteOrder.Uint64(d.buf[d.pos:])))
+		d.pos += 8
+	}
+	d.ends = append(d.ends, len(d.coords))
+}
+
+// polygon reads the rings of a polygon into d.coords and d.ends, and
+// appends the end of the polygon to d.parts.
+func (d *flatDecoder) polygon(byteOrder binary.ByteOrder) {
+	n := d.uint32(byteOrder)
+	for i := uint32(0); i < n && d.err == nil; i++ {
+		d.points(byteOrder)
+	}
+	if d.sizing {
+		d.nParts++
+		return
+	}
+	d.parts = append(d.parts, len(d.ends))
+}
+
+// finish returns any error that occurred while decoding.
+func (d *flatDecoder) finish() error {
+	if d.err == nil && d.pos != len(d.buf) {
+		d.err = fmt.Errorf("wkb: %d unexpected bytes after geometry", len(d.buf)-d.pos)
+	}
+	return d.err
+}
+
+func writeFlatPoints(w io.Writer, byteOrder binary.ByteOrder, coords []float64) error {
+	if err := binary.Write(w, byteOrder, uint32(len(coords)/2)); err != nil {
+		return err
+	}
+	return binary.Write(w, byteOrder, coords)
+}
+
+// writeFlatHeader writes the byte order and type of a geometry within a
+// multi-geometry.
+func writeFlatHeader(w io.Writer, byteOrder binary.ByteOrder, t uint32) error {
+	wkbByteOrder := uint8(wkbNDR)
+	if byteOrder == XDR {
+		wkbByteOrder = wkbXDR
+	}
+	return writeMany(w, byteOrder, wkbByteOrder, t)
+}
+
+func writeFlatMultiPolygon(w io.Writer, byteOrder binary.ByteOrder, f geom.FlatMultiPolygon) error {
+	if err := binary.Write(w, byteOrder, uint32(len(f.PolygonEnds))); err != nil {
+		return err
+	}
+	var ring, start int
+	for _, polygonEnd := range f.PolygonEnds {
+		if err := writeFlatHeader(w, byteOrder, wkbPolygon); err != nil {
+			return err
+		}
+		if err := binary.Write(w, byteOrder, uint32(polygonEnd-ring)); err != nil {
+			return err
+		}
+		for ; ring < polygonEnd; ring++ {
+			if err := writeFlatPoints(w, byteOrder, f.Coords[start:f.RingEnds[ring]]); err != nil {
+				return err
+			}
+			start = f.RingEnds[ring]
+		}
+	}
+	return nil
+}
+
+func writeFlatMultiLineString(w io.Writer, byteOrder binary.ByteOrder, f geom.FlatMultiLineString) error {
+	if err := binary.Write(w, byteOrder, uint32(len(f.Ends))); err != nil {
+		return err
+	}
+	var start int
+	for _, end := range f.Ends {
+		if err := writeFlatHeader(w, byteOrder, wkbLineString); err != nil {
+			return err
+		}
+		if err := writeFlatPoints(w, byteOrder, f.Coords[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
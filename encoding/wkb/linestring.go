@@ -17,3 +17,27 @@ func lineStringReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error
 func writeLineString(w io.Writer, byteOrder binary.ByteOrder, lineString geom.LineString) error {
 	return writePoints(w, byteOrder, lineString)
 }
+
+func lineStringZReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	points, err := readPointsZ(r, byteOrder)
+	if err != nil {
+		return nil, err
+	}
+	return geom.LineStringZ(points), nil
+}
+
+func writeLineStringZ(w io.Writer, byteOrder binary.ByteOrder, lineString geom.LineStringZ) error {
+	return writePointsZ(w, byteOrder, lineString)
+}
+
+func lineStringMReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	points, err := readPointsM(r, byteOrder)
+	if err != nil {
+		return nil, err
+	}
+	return geom.LineStringM(points), nil
+}
+
+func writeLineStringM(w io.Writer, byteOrder binary.ByteOrder, lineString geom.LineStringM) error {
+	return writePointsM(w, byteOrder, lineString)
+}
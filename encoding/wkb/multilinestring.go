@@ -37,3 +37,65 @@ func writeMultiLineString(w io.Writer, byteOrder binary.ByteOrder, multiLineStri
 	}
 	return nil
 }
+
+func multiLineStringZReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	var n uint32
+	if err := binary.Read(r, byteOrder, &n); err != nil {
+		return nil, err
+	}
+	lineStrings := make(geom.MultiLineStringZ, n)
+	for i := uint32(0); i < n; i++ {
+		g, err := Read(r)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if lineStrings[i], ok = g.(geom.LineStringZ); !ok {
+			return nil, &UnexpectedGeometryError{g}
+		}
+	}
+	return lineStrings, nil
+}
+
+func writeMultiLineStringZ(w io.Writer, byteOrder binary.ByteOrder, multiLineStringZ geom.MultiLineStringZ) error {
+	if err := binary.Write(w, byteOrder, uint32(len(multiLineStringZ))); err != nil {
+		return err
+	}
+	for _, g := range multiLineStringZ {
+		if err := Write(w, byteOrder, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func multiLineStringMReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	var n uint32
+	if err := binary.Read(r, byteOrder, &n); err != nil {
+		return nil, err
+	}
+	lineStrings := make(geom.MultiLineStringM, n)
+	for i := uint32(0); i < n; i++ {
+		g, err := Read(r)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if lineStrings[i], ok = g.(geom.LineStringM); !ok {
+			return nil, &UnexpectedGeometryError{g}
+		}
+	}
+	return lineStrings, nil
+}
+
+func writeMultiLineStringM(w io.Writer, byteOrder binary.ByteOrder, multiLineStringM geom.MultiLineStringM) error {
+	if err := binary.Write(w, byteOrder, uint32(len(multiLineStringM))); err != nil {
+		return err
+	}
+	for _, g := range multiLineStringM {
+		if err := Write(w, byteOrder, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -37,3 +37,34 @@ func writeMultiPoint(w io.Writer, byteOrder binary.ByteOrder, multiPoint geom.Mu
 	}
 	return nil
 }
+
+func multiPointZReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	var n uint32
+	if err := binary.Read(r, byteOrder, &n); err != nil {
+		return nil, err
+	}
+	points := make(geom.MultiPointZ, n)
+	for i := uint32(0); i < n; i++ {
+		g, err := Read(r)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if points[i], ok = g.(geom.PointZ); !ok {
+			return nil, &UnexpectedGeometryError{g}
+		}
+	}
+	return points, nil
+}
+
+func writeMultiPointZ(w io.Writer, byteOrder binary.ByteOrder, multiPointZ geom.MultiPointZ) error {
+	if err := binary.Write(w, byteOrder, uint32(len(multiPointZ))); err != nil {
+		return err
+	}
+	for _, g := range multiPointZ {
+		if err := Write(w, byteOrder, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
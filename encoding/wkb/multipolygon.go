@@ -37,3 +37,34 @@ func writeMultiPolygon(w io.Writer, byteOrder binary.ByteOrder, multiPolygon geo
 	}
 	return nil
 }
+
+func multiPolygonZReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	var n uint32
+	if err := binary.Read(r, byteOrder, &n); err != nil {
+		return nil, err
+	}
+	polygons := make(geom.MultiPolygonZ, n)
+	for i := uint32(0); i < n; i++ {
+		g, err := Read(r)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if polygons[i], ok = g.(geom.PolygonZ); !ok {
+			return nil, &UnexpectedGeometryError{g}
+		}
+	}
+	return polygons, nil
+}
+
+func writeMultiPolygonZ(w io.Writer, byteOrder binary.ByteOrder, multiPolygonZ geom.MultiPolygonZ) error {
+	if err := binary.Write(w, byteOrder, uint32(len(multiPolygonZ))); err != nil {
+		return err
+	}
+	for _, g := range multiPolygonZ {
+		if err := Write(w, byteOrder, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
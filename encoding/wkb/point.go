@@ -49,3 +49,53 @@ func writePointss(w io.Writer, byteOrder binary.ByteOrder, pointss []geom.Path)
 	return nil
 
 }
+
+func pointZReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	point := geom.PointZ{}
+	if err := binary.Read(r, byteOrder, &point); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+func readPointsZ(r io.Reader, byteOrder binary.ByteOrder) ([]geom.PointZ, error) {
+	var numPoints uint32
+	if err := binary.Read(r, byteOrder, &numPoints); err != nil {
+		return nil, err
+	}
+	points := make([]geom.PointZ, numPoints)
+	if err := binary.Read(r, byteOrder, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func writePointZ(w io.Writer, byteOrder binary.ByteOrder, point geom.PointZ) error {
+	return binary.Write(w, byteOrder, &point)
+}
+
+func writePointsZ(w io.Writer, byteOrder binary.ByteOrder, points []geom.PointZ) error {
+	if err := binary.Write(w, byteOrder, uint32(len(points))); err != nil {
+		return err
+	}
+	return binary.Write(w, byteOrder, &points)
+}
+
+func readPointsM(r io.Reader, byteOrder binary.ByteOrder) ([]geom.PointM, error) {
+	var numPoints uint32
+	if err := binary.Read(r, byteOrder, &numPoints); err != nil {
+		return nil, err
+	}
+	points := make([]geom.PointM, numPoints)
+	if err := binary.Read(r, byteOrder, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func writePointsM(w io.Writer, byteOrder binary.ByteOrder, points []geom.PointM) error {
+	if err := binary.Write(w, byteOrder, uint32(len(points))); err != nil {
+		return err
+	}
+	return binary.Write(w, byteOrder, &points)
+}
@@ -25,3 +25,31 @@ func polygonReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
 func writePolygon(w io.Writer, byteOrder binary.ByteOrder, polygon geom.Polygon) error {
 	return writePointss(w, byteOrder, polygon)
 }
+
+func polygonZReader(r io.Reader, byteOrder binary.ByteOrder) (geom.Geom, error) {
+	var numRings uint32
+	if err := binary.Read(r, byteOrder, &numRings); err != nil {
+		return nil, err
+	}
+	rings := make([]geom.PathZ, numRings)
+	for i := uint32(0); i < numRings; i++ {
+		points, err := readPointsZ(r, byteOrder)
+		if err != nil {
+			return nil, err
+		}
+		rings[i] = points
+	}
+	return geom.PolygonZ(rings), nil
+}
+
+func writePolygonZ(w io.Writer, byteOrder binary.ByteOrder, polygon geom.PolygonZ) error {
+	if err := binary.Write(w, byteOrder, uint32(len(polygon))); err != nil {
+		return err
+	}
+	for _, ring := range polygon {
+		if err := writePointsZ(w, byteOrder, ring); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -26,6 +26,26 @@ const (
 	wkbPolyhedralSurface  = 15
 	wkbTIN                = 16
 	wkbTriangle           = 17
+
+	// ISO WKB geometry types with Z coordinates.
+	wkbPointZ           = 1001
+	wkbLineStringZ      = 1002
+	wkbPolygonZ         = 1003
+	wkbMultiPointZ      = 1004
+	wkbMultiLineStringZ = 1005
+	wkbMultiPolygonZ    = 1006
+
+	// ISO WKB geometry types with M coordinates.
+	wkbLineStringM      = 2002
+	wkbMultiLineStringM = 2005
+)
+
+// Flags used by PostGIS extended WKB (EWKB) to mark geometry types that
+// have Z or M coordinates or a spatial reference ID.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
 )
 
 var (
@@ -62,8 +82,19 @@ func init() {
 	wkbReaders[wkbMultiLineString] = multiLineStringReader
 	wkbReaders[wkbMultiPolygon] = multiPolygonReader
 	wkbReaders[wkbGeometryCollection] = geometryCollectionReader
+	wkbReaders[wkbPointZ] = pointZReader
+	wkbReaders[wkbLineStringZ] = lineStringZReader
+	wkbReaders[wkbPolygonZ] = polygonZReader
+	wkbReaders[wkbMultiPointZ] = multiPointZReader
+	wkbReaders[wkbMultiLineStringZ] = multiLineStringZReader
+	wkbReaders[wkbMultiPolygonZ] = multiPolygonZReader
+	wkbReaders[wkbLineStringM] = lineStringMReader
+	wkbReaders[wkbMultiLineStringM] = multiLineStringMReader
 }
 
+// Read reads a geometry from r. Both ISO WKB and PostGIS extended WKB
+// (EWKB) geometries with Z or M coordinates are supported; the spatial
+// reference IDs of EWKB geometries are discarded.
 func Read(r io.Reader) (geom.Geom, error) {
 
 	var wkbByteOrder uint8
@@ -84,6 +115,22 @@ func Read(r io.Reader) (geom.Geom, error) {
 	if err := binary.Read(r, byteOrder, &wkbGeometryType); err != nil {
 		return nil, err
 	}
+	if wkbGeometryType&(ewkbZ|ewkbM|ewkbSRID) != 0 {
+		if wkbGeometryType&ewkbSRID != 0 {
+			var srid uint32
+			if err := binary.Read(r, byteOrder, &srid); err != nil {
+				return nil, err
+			}
+		}
+		t := wkbGeometryType &^ (ewkbZ | ewkbM | ewkbSRID)
+		if wkbGeometryType&ewkbZ != 0 {
+			t += 1000
+		}
+		if wkbGeometryType&ewkbM != 0 {
+			t += 2000
+		}
+		wkbGeometryType = t
+	}
 
 	if reader, ok := wkbReaders[wkbGeometryType]; ok {
 		return reader(r, byteOrder)
@@ -135,6 +182,26 @@ func Write(w io.Writer, byteOrder binary.ByteOrder, g geom.Geom) error {
 		wkbGeometryType = wkbMultiPolygon
 	case geom.GeometryCollection:
 		wkbGeometryType = wkbGeometryCollection
+	case geom.PointZ:
+		wkbGeometryType = wkbPointZ
+	case geom.LineStringZ:
+		wkbGeometryType = wkbLineStringZ
+	case geom.PolygonZ:
+		wkbGeometryType = wkbPolygonZ
+	case geom.MultiPointZ:
+		wkbGeometryType = wkbMultiPointZ
+	case geom.MultiLineStringZ:
+		wkbGeometryType = wkbMultiLineStringZ
+	case geom.MultiPolygonZ:
+		wkbGeometryType = wkbMultiPolygonZ
+	case geom.LineStringM:
+		wkbGeometryType = wkbLineStringM
+	case geom.MultiLineStringM:
+		wkbGeometryType = wkbMultiLineStringM
+	case geom.FlatMultiPolygon:
+		wkbGeometryType = wkbMultiPolygon
+	case geom.FlatMultiLineString:
+		wkbGeometryType = wkbMultiLineString
 	default:
 		return &UnsupportedGeometryError{reflect.TypeOf(g)}
 	}
@@ -156,6 +223,26 @@ func Write(w io.Writer, byteOrder binary.ByteOrder, g geom.Geom) error {
 		return writeMultiPolygon(w, byteOrder, g.(geom.MultiPolygon))
 	case geom.GeometryCollection:
 		return writeGeometryCollection(w, byteOrder, g.(geom.GeometryCollection))
+	case geom.PointZ:
+		return writePointZ(w, byteOrder, g.(geom.PointZ))
+	case geom.LineStringZ:
+		return writeLineStringZ(w, byteOrder, g.(geom.LineStringZ))
+	case geom.PolygonZ:
+		return writePolygonZ(w, byteOrder, g.(geom.PolygonZ))
+	case geom.MultiPointZ:
+		return writeMultiPointZ(w, byteOrder, g.(geom.MultiPointZ))
+	case geom.MultiLineStringZ:
+		return writeMultiLineStringZ(w, byteOrder, g.(geom.MultiLineStringZ))
+	case geom.MultiPolygonZ:
+		return writeMultiPolygonZ(w, byteOrder, g.(geom.MultiPolygonZ))
+	case geom.LineStringM:
+		return writeLineStringM(w, byteOrder, g.(geom.LineStringM))
+	case geom.MultiLineStringM:
+		return writeMultiLineStringM(w, byteOrder, g.(geom.MultiLineStringM))
+	case geom.FlatMultiPolygon:
+		return writeFlatMultiPolygon(w, byteOrder, g.(geom.FlatMultiPolygon))
+	case geom.FlatMultiLineString:
+		return writeFlatMultiLineString(w, byteOrder, g.(geom.FlatMultiLineString))
 	default:
 		return &UnsupportedGeometryError{reflect.TypeOf(g)}
 	}
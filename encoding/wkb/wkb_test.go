@@ -1,6 +1,7 @@
 package wkb
 
 import (
+	"encoding/binary"
 	"github.com/ctessum/geom"
 	"reflect"
 	"testing"
@@ -60,3 +61,112 @@ func TestWKB(t *testing.T) {
 	}
 
 }
+
+func TestWKBZM(t *testing.T) {
+	var testCases = []struct {
+		g   geom.Geom
+		ndr []byte
+	}{
+		{
+			g:   geom.PointZ{X: 1, Y: 2, Z: 3},
+			ndr: []byte("\x01\xe9\x03\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@\x00\x00\x00\x00\x00\x00\x08@"),
+		},
+		{g: geom.LineStringZ{{1, 2, 3}, {4, 5, 6}}},
+		{g: geom.PolygonZ{{{0, 0, 1}, {1, 0, 2}, {1, 1, 3}, {0, 0, 1}}}},
+		{g: geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}}},
+		{g: geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}, {{7, 8, 9}, {10, 11, 12}}}},
+		{g: geom.MultiPolygonZ{{{{0, 0, 1}, {1, 0, 2}, {1, 1, 3}, {0, 0, 1}}}}},
+		{g: geom.LineStringM{{1, 2, 3}, {4, 5, 6}}},
+		{g: geom.MultiLineStringM{{{1, 2, 3}, {4, 5, 6}}, {{7, 8, 9}, {10, 11, 12}}}},
+	}
+	for i, tc := range testCases {
+		for _, byteOrder := range []binary.ByteOrder{XDR, NDR} {
+			b, err := Encode(tc.g, byteOrder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.ndr != nil && byteOrder == NDR && !reflect.DeepEqual(b, tc.ndr) {
+				t.Errorf("%d: have %#v, want %#v", i, b, tc.ndr)
+			}
+			if got, err := Decode(b); err != nil || !reflect.DeepEqual(got, tc.g) {
+				t.Errorf("%d: have %#v, %v, want %#v", i, got, err, tc.g)
+			}
+		}
+	}
+
+	// PostGIS extended WKB, with a Z flag and a spatial reference ID.
+	ewkb := []byte("\x01\x01\x00\x00\xa0\xe6\x10\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@\x00\x00\x00\x00\x00\x00\x08@")
+	want := geom.PointZ{X: 1, Y: 2, Z: 3}
+	if got, err := Decode(ewkb); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("EWKB: have %#v, %v, want %#v", got, err, want)
+	}
+}
+
+func TestWKBFlat(t *testing.T) {
+	mp := geom.MultiPolygon{
+		{
+			{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0}},
+			{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 1, Y: 1}},
+		},
+		{{{X: 5, Y: 5}, {X: 6, Y: 5}, {X: 6, Y: 6}, {X: 5, Y: 5}}},
+	}
+	ml := geom.MultiLineString{{{X: 1, Y: 2}, {X: 3, Y: 4}}, {{X: 5, Y: 6}, {X: 7, Y: 8}, {X: 9, Y: 10}}}
+	testCases := []struct {
+		g, want geom.Geom
+	}{
+		{g: mp, want: geom.NewFlatMultiPolygon(mp)},
+		{g: mp[0], want: geom.NewFlatMultiPolygon(mp[0])},
+		{g: ml, want: geom.NewFlatMultiLineString(ml)},
+		{g: ml[1], want: geom.NewFlatMultiLineString(ml[1:])},
+	}
+	for i, tc := range testCases {
+		for _, byteOrder := range []binary.ByteOrder{NDR, XDR} {
+			b, err := Encode(tc.g, byteOrder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := DecodeFlat(b)
+			if err != nil || !got.Similar(tc.want, 1e-12) {
+				t.Errorf("%d: have %#v, %v, want %#v", i, got, err, tc.want)
+			}
+			if err != nil {
+				continue
+			}
+			// Each slice is allocated once at its final size.
+			var sizes [][2]int
+			switch f := got.(type) {
+			case geom.FlatMultiPolygon:
+				sizes = [][2]int{{len(f.Coords), cap(f.Coords)}, {len(f.RingEnds), cap(f.RingEnds)}, {len(f.PolygonEnds), cap(f.PolygonEnds)}}
+			case geom.FlatMultiLineString:
+				sizes = [][2]int{{len(f.Coords), cap(f.Coords)}, {len(f.Ends), cap(f.Ends)}}
+			}
+			for _, size := range sizes {
+				if size[0] != size[1] {
+					t.Errorf("%d: slice length %d, capacity %d", i, size[0], size[1])
+				}
+			}
+			// Flat geometries are encoded as multi-geometries.
+			flat, err := Encode(got, byteOrder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			multi := tc.g
+			switch g := tc.g.(type) {
+			case geom.Polygon:
+				multi = geom.MultiPolygon{g}
+			case geom.LineString:
+				multi = geom.MultiLineString{g}
+			}
+			if want, _ := Encode(multi, byteOrder); !reflect.DeepEqual(flat, want) {
+				t.Errorf("%d: encoding: have %#v, want %#v", i, flat, want)
+			}
+		}
+	}
+
+	if _, err := DecodeFlat([]byte("\x01\x03\x00\x00\x00\x01\x00\x00\x00\x04\x00\x00\x00")); err == nil {
+		t.Error("truncated: have no error")
+	}
+	if _, err := DecodeFlat([]byte("\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0?\x00\x00\x00\x00\x00\x00\x00@")); err == nil {
+		t.Error("point: have no error")
+	}
+}
@@ -22,6 +22,27 @@ func Encode(g geom.Geom) ([]byte, error) {
 	case geom.MultiPolygon:
 		multiPolygon := g.(geom.MultiPolygon)
 		return appendMultiPolygonWKT(nil, multiPolygon), nil
+	case geom.PointZ:
+		point := g.(geom.PointZ)
+		return appendPointZWKT(nil, &point), nil
+	case geom.MultiPointZ:
+		return appendMultiPointZWKT(nil, g.(geom.MultiPointZ)), nil
+	case geom.LineStringZ:
+		return appendLineStringZWKT(nil, g.(geom.LineStringZ)), nil
+	case geom.MultiLineStringZ:
+		return appendMultiLineStringZWKT(nil, g.(geom.MultiLineStringZ)), nil
+	case geom.PolygonZ:
+		return appendPolygonZWKT(nil, g.(geom.PolygonZ)), nil
+	case geom.MultiPolygonZ:
+		return appendMultiPolygonZWKT(nil, g.(geom.MultiPolygonZ)), nil
+	case geom.LineStringM:
+		return appendLineStringMWKT(nil, g.(geom.LineStringM)), nil
+	case geom.MultiLineStringM:
+		return appendMultiLineStringMWKT(nil, g.(geom.MultiLineStringM)), nil
+	case geom.FlatMultiPolygon:
+		return appendMultiPolygonWKT(nil, g.(geom.FlatMultiPolygon).MultiPolygon()), nil
+	case geom.FlatMultiLineString:
+		return appendMultiLineStringWKT(nil, g.(geom.FlatMultiLineString).MultiLineString()), nil
 	default:
 		return nil, &UnsupportedGeometryError{reflect.TypeOf(g)}
 	}
@@ -10,3 +10,17 @@ func appendLineStringWKT(dst []byte, lineString geom.LineString) []byte {
 	dst = append(dst, ')')
 	return dst
 }
+
+func appendLineStringZWKT(dst []byte, lineString geom.LineStringZ) []byte {
+	dst = append(dst, []byte("LINESTRING Z (")...)
+	dst = appendPointsZCoords(dst, lineString)
+	dst = append(dst, ')')
+	return dst
+}
+
+func appendLineStringMWKT(dst []byte, lineString geom.LineStringM) []byte {
+	dst = append(dst, []byte("LINESTRING M (")...)
+	dst = appendPointsMCoords(dst, lineString)
+	dst = append(dst, ')')
+	return dst
+}
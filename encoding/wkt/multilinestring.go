@@ -19,3 +19,33 @@ func appendMultiLineStringWKT(dst []byte,
 	dst = append(dst, ')')
 	return dst
 }
+
+func appendMultiLineStringZWKT(dst []byte,
+	multiLineString geom.MultiLineStringZ) []byte {
+	dst = append(dst, []byte("MULTILINESTRING Z (")...)
+	for i, ls := range multiLineString {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '(')
+		dst = appendPointsZCoords(dst, ls)
+		dst = append(dst, ')')
+	}
+	dst = append(dst, ')')
+	return dst
+}
+
+func appendMultiLineStringMWKT(dst []byte,
+	multiLineString geom.MultiLineStringM) []byte {
+	dst = append(dst, []byte("MULTILINESTRING M (")...)
+	for i, ls := range multiLineString {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '(')
+		dst = appendPointsMCoords(dst, ls)
+		dst = append(dst, ')')
+	}
+	dst = append(dst, ')')
+	return dst
+}
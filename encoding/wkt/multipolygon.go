@@ -19,3 +19,18 @@ func appendMultiPolygonWKT(dst []byte,
 	dst = append(dst, ')')
 	return dst
 }
+
+func appendMultiPolygonZWKT(dst []byte,
+	multiPolygon geom.MultiPolygonZ) []byte {
+	dst = append(dst, []byte("MULTIPOLYGON Z (")...)
+	for i, pg := range multiPolygon {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '(')
+		dst = appendPointssZCoords(dst, pg)
+		dst = append(dst, ')')
+	}
+	dst = append(dst, ')')
+	return dst
+}
@@ -40,3 +40,62 @@ func appendPointWKT(dst []byte, point *geom.Point) []byte {
 	dst = append(dst, ')')
 	return dst
 }
+
+func appendPointZCoords(dst []byte, point *geom.PointZ) []byte {
+	dst = strconv.AppendFloat(dst, point.X, 'g', -1, 64)
+	dst = append(dst, ' ')
+	dst = strconv.AppendFloat(dst, point.Y, 'g', -1, 64)
+	dst = append(dst, ' ')
+	dst = strconv.AppendFloat(dst, point.Z, 'g', -1, 64)
+	return dst
+}
+
+func appendPointsZCoords(dst []byte, points []geom.PointZ) []byte {
+	for i, point := range points {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendPointZCoords(dst, &point)
+	}
+	return dst
+}
+
+func appendPointssZCoords(dst []byte, pointss []geom.PathZ) []byte {
+	for i, points := range pointss {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '(')
+		dst = appendPointsZCoords(dst, points)
+		dst = append(dst, ')')
+	}
+	return dst
+}
+
+func appendPointZWKT(dst []byte, point *geom.PointZ) []byte {
+	dst = append(dst, []byte("POINT Z (")...)
+	dst = appendPointZCoords(dst, point)
+	dst = append(dst, ')')
+	return dst
+}
+
+func appendMultiPointZWKT(dst []byte, multiPoint geom.MultiPointZ) []byte {
+	dst = append(dst, []byte("MULTIPOINT Z (")...)
+	dst = appendPointsZCoords(dst, multiPoint)
+	dst = append(dst, ')')
+	return dst
+}
+
+func appendPointsMCoords(dst []byte, points []geom.PointM) []byte {
+	for i, point := range points {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		dst = strconv.AppendFloat(dst, point.X, 'g', -1, 64)
+		dst = append(dst, ' ')
+		dst = strconv.AppendFloat(dst, point.Y, 'g', -1, 64)
+		dst = append(dst, ' ')
+		dst = strconv.AppendFloat(dst, point.M, 'g', -1, 64)
+	}
+	return dst
+}
@@ -10,3 +10,10 @@ func appendPolygonWKT(dst []byte, polygon geom.Polygon) []byte {
 	dst = append(dst, ')')
 	return dst
 }
+
+func appendPolygonZWKT(dst []byte, polygon geom.PolygonZ) []byte {
+	dst = append(dst, []byte("POLYGON Z (")...)
+	dst = appendPointssZCoords(dst, polygon)
+	dst = append(dst, ')')
+	return dst
+}
@@ -23,6 +23,46 @@ func TestWKT(t *testing.T) {
 			geom.Polygon([]geom.Path{{{1, 2}, {3, 4}, {5, 6}, {1, 2}}}),
 			[]byte(`POLYGON((1 2,3 4,5 6,1 2))`),
 		},
+		{
+			geom.PointZ{1, 2, 3},
+			[]byte(`POINT Z (1 2 3)`),
+		},
+		{
+			geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}},
+			[]byte(`MULTIPOINT Z (1 2 3,4 5 6)`),
+		},
+		{
+			geom.LineStringZ{{1, 2, 3}, {4, 5, 6}},
+			[]byte(`LINESTRING Z (1 2 3,4 5 6)`),
+		},
+		{
+			geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}, {{7, 8, 9}, {1, 2, 3}}},
+			[]byte(`MULTILINESTRING Z ((1 2 3,4 5 6),(7 8 9,1 2 3))`),
+		},
+		{
+			geom.PolygonZ{{{1, 2, 0}, {3, 4, 1}, {5, 6, 2}, {1, 2, 0}}},
+			[]byte(`POLYGON Z ((1 2 0,3 4 1,5 6 2,1 2 0))`),
+		},
+		{
+			geom.MultiPolygonZ{{{{1, 2, 0}, {3, 4, 1}, {5, 6, 2}, {1, 2, 0}}}},
+			[]byte(`MULTIPOLYGON Z (((1 2 0,3 4 1,5 6 2,1 2 0)))`),
+		},
+		{
+			geom.LineStringM{{1, 2, 0}, {4, 5, 10}},
+			[]byte(`LINESTRING M (1 2 0,4 5 10)`),
+		},
+		{
+			geom.MultiLineStringM{{{1, 2, 0}, {4, 5, 10}}, {{7, 8, 12}, {1, 2, 20}}},
+			[]byte(`MULTILINESTRING M ((1 2 0,4 5 10),(7 8 12,1 2 20))`),
+		},
+		{
+			geom.NewFlatMultiLineString(geom.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}}),
+			[]byte(`MULTILINESTRING((1 2,3 4),(5 6,7 8))`),
+		},
+		{
+			geom.NewFlatMultiPolygon(geom.Polygon{{{1, 2}, {3, 4}, {5, 6}, {1, 2}}}),
+			[]byte(`MULTIPOLYGON(((1 2,3 4,5 6,1 2)))`),
+		},
 	}
 	for _, tc := range testCases {
 		if got, err := Encode(tc.g); err != nil || !reflect.DeepEqual(got, tc.wkt) {
@@ -0,0 +1,417 @@
+package geom
+
+import (
+	"math"
+
+	"github.com/ctessum/geom/proj"
+)
+
+// FlatMultiPolygon is a compact representation of one or more polygons,
+// where all of the coordinates are stored in a single slice. It uses far
+// fewer allocations than a MultiPolygon, which makes it suitable for
+// holding large numbers of polygons in memory. The first ring of each
+// polygon is its outer ring and the remaining rings are holes.
+//
+// Measurements such as Area, Bounds and Centroid are calculated directly
+// from the flat coordinates. Other operations, such as Intersection and
+// Simplify, convert the receiver to a MultiPolygon first, and return
+// their results as the regular slice types. Functions that accept any
+// geometry, such as Smooth and Snap, treat it as a MultiPolygon.
+type FlatMultiPolygon struct {
+	// Coords holds the X and Y coordinates of the points, interleaved:
+	// x0, y0, x1, y1, and so on.
+	Coords []float64
+
+	// RingEnds holds the index in Coords just past the end of each ring.
+	RingEnds []int
+
+	// PolygonEnds holds the index in RingEnds just past the end of each
+	// polygon.
+	PolygonEnds []int
+}
+
+// FlatMultiLineString is a compact representation of one or more
+// LineStrings, where all of the coordinates are stored in a single slice.
+// It is to MultiLineString what FlatMultiPolygon is to MultiPolygon.
+type FlatMultiLineString struct {
+	// Coords holds the X and Y coordinates of the points, interleaved:
+	// x0, y0, x1, y1, and so on.
+	Coords []float64
+
+	// Ends holds the index in Coords just past the end of each
+	// LineString.
+	Ends []int
+}
+
+// NewFlatMultiPolygon returns a FlatMultiPolygon holding a copy of the
+// polygons in p.
+func NewFlatMultiPolygon(p Polygonal) FlatMultiPolygon {
+	polys := p.Polygons()
+	var f FlatMultiPolygon
+	var n, nRings int
+	for _, pp := range polys {
+		nRings += len(pp)
+		for _, r := range pp {
+			n += len(r)
+		}
+	}
+	f.Coords = make([]float64, 0, 2*n)
+	f.RingEnds = make([]int, 0, nRings)
+	f.PolygonEnds = make([]int, 0, len(polys))
+	for _, pp := range polys {
+		for _, r := range pp {
+			f.Coords = appendFlatPoints(f.Coords, r)
+			f.RingEnds = append(f.RingEnds, len(f.Coords))
+		}
+		f.PolygonEnds = append(f.PolygonEnds, len(f.RingEnds))
+	}
+	return f
+}
+
+// NewFlatMultiLineString returns a FlatMultiLineString holding a copy of
+// the LineStrings in ml.
+func NewFlatMultiLineString(ml MultiLineString) FlatMultiLineString {
+	var n int
+	for _, l := range ml {
+		n += len(l)
+	}
+	f := FlatMultiLineString{
+		Coords: make([]float64, 0, 2*n),
+		Ends:   make([]int, 0, len(ml)),
+	}
+	for _, l := range ml {
+		f.Coords = appendFlatPoints(f.Coords, l)
+		f.Ends = append(f.Ends, len(f.Coords))
+	}
+	return f
+}
+
+func appendFlatPoints(coords []float64, points []Point) []float64 {
+	for _, p := range points {
+		coords = append(coords, p.X, p.Y)
+	}
+	return coords
+}
+
+// flatPath returns coords[start:end] as a Path.
+func flatPath(coords []float64, start, end int) Path {
+	o := make(Path, (end-start)/2)
+	for i := range o {
+		o[i] = Point{X: coords[start+2*i], Y: coords[start+2*i+1]}
+	}
+	return o
+}
+
+// flatBounds returns the bounds of coords.
+func flatBounds(coords []float64) *Bounds {
+	b := NewBounds()
+	for i := 0; i < len(coords); i += 2 {
+		b.extendPoint(Point{X: coords[i], Y: coords[i+1]})
+	}
+	return b
+}
+
+// flatPoints returns an iterator for the points in coords.
+func flatPoints(coords []float64) func() Point {
+	var i int
+	return func() Point {
+		i += 2
+		return Point{X: coords[i-2], Y: coords[i-1]}
+	}
+}
+
+// transformFlat returns coords transformed by t.
+func transformFlat(coords []float64, t proj.Transformer) ([]float64, error) {
+	o := make([]float64, len(coords))
+	var err error
+	for i := 0; i < len(coords); i += 2 {
+		o[i], o[i+1], err = t(coords[i], coords[i+1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// flatRingArea returns the signed area of the ring in coords[start:end]
+// and the sums used to calculate its centroid.
+func flatRingArea(coords []float64, start, end int) (a, cx, cy float64) {
+	n := (end - start) / 2
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		x0, y0 := coords[start+2*i], coords[start+2*i+1]
+		x1, y1 := coords[start+2*j], coords[start+2*j+1]
+		cross := x0*y1 - x1*y0
+		a += cross
+		cx += (x0 + x1) * cross
+		cy += (y0 + y1) * cross
+	}
+	return a / 2, cx, cy
+}
+
+// ringStart returns the index in Coords of the start of ring i.
+func (f FlatMultiPolygon) ringStart(i int) int {
+	if i == 0 {
+		return 0
+	}
+	return f.RingEnds[i-1]
+}
+
+// polygonStart returns the index in RingEnds of the first ring of
+// polygon i.
+func (f FlatMultiPolygon) polygonStart(i int) int {
+	if i == 0 {
+		return 0
+	}
+	return f.PolygonEnds[i-1]
+}
+
+// Polygon returns a copy of polygon i of f.
+func (f FlatMultiPolygon) Polygon(i int) Polygon {
+	start, end := f.polygonStart(i), f.PolygonEnds[i]
+	o := make(Polygon, end-start)
+	for j := range o {
+		o[j] = flatPath(f.Coords, f.ringStart(start+j), f.RingEnds[start+j])
+	}
+	return o
+}
+
+// MultiPolygon returns a copy of f as a MultiPolygon.
+func (f FlatMultiPolygon) MultiPolygon() MultiPolygon {
+	o := make(MultiPolygon, len(f.PolygonEnds))
+	for i := range o {
+		o[i] = f.Polygon(i)
+	}
+	return o
+}
+
+// Polygons returns a copy of the polygons in f.
+func (f FlatMultiPolygon) Polygons() []Polygon { return f.MultiPolygon() }
+
+// Bounds gives the rectangular extents of f.
+func (f FlatMultiPolygon) Bounds() *Bounds { return flatBounds(f.Coords) }
+
+// Similar determines whether two geometries are similar within tolerance.
+// g must also be a FlatMultiPolygon.
+func (f FlatMultiPolygon) Similar(g Geom, tolerance float64) bool {
+	f2, ok := g.(FlatMultiPolygon)
+	return ok && f.MultiPolygon().Similar(f2.MultiPolygon(), tolerance)
+}
+
+// Transform shifts the coordinates of f according to t. The ring and
+// polygon offsets of the result are shared with f.
+func (f FlatMultiPolygon) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return f, nil
+	}
+	coords, err := transformFlat(f.Coords, t)
+	if err != nil {
+		return nil, err
+	}
+	return FlatMultiPolygon{Coords: coords, RingEnds: f.RingEnds, PolygonEnds: f.PolygonEnds}, nil
+}
+
+// Len returns the number of points in the receiver.
+func (f FlatMultiPolygon) Len() int { return len(f.Coords) / 2 }
+
+// Points returns an iterator for the points in the receiver.
+func (f FlatMultiPolygon) Points() func() Point { return flatPoints(f.Coords) }
+
+// Area returns the combined area of the polygons in f, where the outer
+// ring of each polygon adds to the area and the holes subtract from it.
+func (f FlatMultiPolygon) Area() float64 {
+	var a float64
+	f.eachRing(func(start, end int, hole bool) {
+		ra, _, _ := flatRingArea(f.Coords, start, end)
+		if hole {
+			a -= math.Abs(ra)
+		} else {
+			a += math.Abs(ra)
+		}
+	})
+	return a
+}
+
+// Centroid calculates the centroid of f.
+func (f FlatMultiPolygon) Centroid() Point {
+	var A, xA, yA float64
+	f.eachRing(func(start, end int, hole bool) {
+		a, cx, cy := flatRingArea(f.Coords, start, end)
+		if a == 0 {
+			return
+		}
+		// Scale the ring's contribution so that outer rings add to the
+		// area and holes subtract from it, regardless of winding order.
+		s := 1.
+		if (a < 0) != hole {
+			s = -1
+		}
+		A += s * a
+		xA += s * cx / 6
+		yA += s * cy / 6
+	})
+	return Point{X: xA / A, Y: yA / A}
+}
+
+// eachRing calls fn with the start and end indices in Coords of each ring
+// of f, and whether the ring is a hole.
+func (f FlatMultiPolygon) eachRing(fn func(start, end int, hole bool)) {
+	for i := range f.PolygonEnds {
+		for j := f.polygonStart(i); j < f.PolygonEnds[i]; j++ {
+			fn(f.ringStart(j), f.RingEnds[j], j > f.polygonStart(i))
+		}
+	}
+}
+
+// Intersection returns the area(s) shared by f and p2.
+func (f FlatMultiPolygon) Intersection(p2 Polygonal) Polygonal {
+	return f.MultiPolygon().Intersection(p2)
+}
+
+// Union returns the combination of f and p2.
+func (f FlatMultiPolygon) Union(p2 Polygonal) Polygonal {
+	return f.MultiPolygon().Union(p2)
+}
+
+// XOr returns the area(s) occupied by either f or p2 but not both.
+func (f FlatMultiPolygon) XOr(p2 Polygonal) Polygonal {
+	return f.MultiPolygon().XOr(p2)
+}
+
+// Difference subtracts p2 from f.
+func (f FlatMultiPolygon) Difference(p2 Polygonal) Polygonal {
+	return f.MultiPolygon().Difference(p2)
+}
+
+// Simplify simplifies f as described for MultiPolygon.Simplify, and
+// returns a MultiPolygon.
+func (f FlatMultiPolygon) Simplify(tolerance float64) Geom {
+	return f.MultiPolygon().Simplify(tolerance)
+}
+
+// lineStart returns the index in Coords of the start of LineString i.
+func (f FlatMultiLineString) lineStart(i int) int {
+	if i == 0 {
+		return 0
+	}
+	return f.Ends[i-1]
+}
+
+// LineString returns a copy of LineString i of f.
+func (f FlatMultiLineString) LineString(i int) LineString {
+	return LineString(flatPath(f.Coords, f.lineStart(i), f.Ends[i]))
+}
+
+// MultiLineString returns a copy of f as a MultiLineString.
+func (f FlatMultiLineString) MultiLineString() MultiLineString {
+	o := make(MultiLineString, len(f.Ends))
+	for i := range o {
+		o[i] = f.LineString(i)
+	}
+	return o
+}
+
+// Bounds gives the rectangular extents of f.
+func (f FlatMultiLineString) Bounds() *Bounds { return flatBounds(f.Coords) }
+
+// Similar determines whether two geometries are similar within tolerance.
+// g must also be a FlatMultiLineString.
+func (f FlatMultiLineString) Similar(g Geom, tolerance float64) bool {
+	f2, ok := g.(FlatMultiLineString)
+	return ok && f.MultiLineString().Similar(f2.MultiLineString(), tolerance)
+}
+
+// Transform shifts the coordinates of f according to t. The LineString
+// offsets of the result are shared with f.
+func (f FlatMultiLineString) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return f, nil
+	}
+	coords, err := transformFlat(f.Coords, t)
+	if err != nil {
+		return nil, err
+	}
+	return FlatMultiLineString{Coords: coords, Ends: f.Ends}, nil
+}
+
+// Len returns the number of points in the receiver.
+func (f FlatMultiLineString) Len() int { return len(f.Coords) / 2 }
+
+// Points returns an iterator for the points in the receiver.
+func (f FlatMultiLineString) Points() func() Point { return flatPoints(f.Coords) }
+
+// Length returns the combined length of the LineStrings in f.
+func (f FlatMultiLineString) Length() float64 {
+	var length float64
+	for i := range f.Ends {
+		for j := f.lineStart(i) + 2; j < f.Ends[i]; j += 2 {
+			length += math.Hypot(f.Coords[j]-f.Coords[j-2], f.Coords[j+1]-f.Coords[j-1])
+		}
+	}
+	return length
+}
+
+// Distance calculates the shortest distance from p to f.
+func (f FlatMultiLineString) Distance(p Point) float64 {
+	dist := math.Inf(1)
+	for i := range f.Ends {
+		start := f.lineStart(i)
+		for j := start + 2; j < f.Ends[i]; j += 2 {
+			a := Point{X: f.Coords[j-2], Y: f.Coords[j-1]}
+			b := Point{X: f.Coords[j], Y: f.Coords[j+1]}
+			dist = math.Min(dist, distPointToSegment(p, a, b))
+		}
+	}
+	return dist
+}
+
+// Within calculates whether all of the points of f are within p or
+// touching its edge.
+func (f FlatMultiLineString) Within(p Polygonal) WithinStatus {
+	for i := 0; i < len(f.Coords); i += 2 {
+		if pointInPolygonal(Point{X: f.Coords[i], Y: f.Coords[i+1]}, p) == Outside {
+			return Outside
+		}
+	}
+	return Inside
+}
+
+// Clip returns the part of f that falls within p.
+func (f FlatMultiLineString) Clip(p Polygonal) Linear {
+	return f.MultiLineString().Clip(p)
+}
+
+// Difference returns the part of f that falls outside of p.
+func (f FlatMultiLineString) Difference(p Polygonal) Linear {
+	return f.MultiLineString().Difference(p)
+}
+
+// Intersection returns the points where f crosses or touches l2, and the
+// parts of f that overlap it.
+func (f FlatMultiLineString) Intersection(l2 Linear) (MultiPoint, MultiLineString) {
+	return f.MultiLineString().Intersection(l2)
+}
+
+// Union returns the combination of f and l2, with overlapping parts only
+// included once.
+func (f FlatMultiLineString) Union(l2 Linear) Linear {
+	return f.MultiLineString().Union(l2)
+}
+
+// Simplify simplifies f as described for MultiLineString.Simplify, and
+// returns a MultiLineString.
+func (f FlatMultiLineString) Simplify(tolerance float64) Geom {
+	return f.MultiLineString().Simplify(tolerance)
+}
+
+// Interpolate returns the point the given distance along f, as described
+// for MultiLineString.Interpolate.
+func (f FlatMultiLineString) Interpolate(distance float64) Point {
+	return f.MultiLineString().Interpolate(distance)
+}
+
+// Locate returns the distance along f of the point on f that is closest
+// to p, as described for MultiLineString.Locate.
+func (f FlatMultiLineString) Locate(p Point) float64 {
+	return f.MultiLineString().Locate(p)
+}
@@ -0,0 +1,170 @@
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestFlatMultiPolygon(t *testing.T) {
+	mp := MultiPolygon{
+		{
+			{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0}},
+			{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}, {X: 1, Y: 1}},
+		},
+		{{{X: 5, Y: 5}, {X: 5, Y: 7}, {X: 7, Y: 7}, {X: 7, Y: 5}, {X: 5, Y: 5}}},
+	}
+	var _ Polygonal = FlatMultiPolygon{}
+	f := NewFlatMultiPolygon(mp)
+	if len(f.Coords) != 30 || len(f.RingEnds) != 3 || len(f.PolygonEnds) != 2 {
+		t.Errorf("have %d coordinates, %d rings and %d polygons, want 30, 3 and 2",
+			len(f.Coords), len(f.RingEnds), len(f.PolygonEnds))
+	}
+	if !f.MultiPolygon().Similar(mp, 1e-12) {
+		t.Errorf("round trip: have %v, want %v", f.MultiPolygon(), mp)
+	}
+	if a, want := f.Area(), mp.Area(); math.Abs(a-want) > 1e-12 {
+		t.Errorf("area: have %g, want %g", a, want)
+	}
+	if c, want := f.Centroid(), (Point{X: 54.5 / 19, Y: 54.5 / 19}); !pointSimilar(c, want, 1e-12) {
+		t.Errorf("centroid: have %v, want %v", c, want)
+	}
+	if b, want := f.Bounds(), mp.Bounds(); *b != *want {
+		t.Errorf("bounds: have %v, want %v", b, want)
+	}
+	if f.Len() != mp.Len() {
+		t.Errorf("len: have %d, want %d", f.Len(), mp.Len())
+	}
+	next, nextWant := f.Points(), mp.Points()
+	for i := 0; i < f.Len(); i++ {
+		if p, want := next(), nextWant(); p != want {
+			t.Errorf("point %d: have %v, want %v", i, p, want)
+		}
+	}
+	g, err := f.Transform(func(x, y float64) (float64, float64, error) { return 2 * x, y, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := g.(Polygonal).Area(); a != 2*f.Area() {
+		t.Errorf("transformed area: have %g, want %g", a, 2*f.Area())
+	}
+	if a := f.Intersection(&Bounds{Min: Point{X: 3, Y: 3}, Max: Point{X: 6, Y: 6}}).Area(); math.Abs(a-2) > 1e-9 {
+		t.Errorf("intersection area: have %g, want 2", a)
+	}
+	if d := Distance(f, Point{X: 1.5, Y: 1.5}); d != 0.5 {
+		t.Errorf("distance: have %g, want 0.5", d)
+	}
+}
+
+func TestFlatMultiLineString(t *testing.T) {
+	ml := MultiLineString{{{X: 0, Y: 0}, {X: 3, Y: 4}}, {{X: 10, Y: 0}, {X: 10, Y: 2}, {X: 12, Y: 2}}}
+	var _ Linear = FlatMultiLineString{}
+	f := NewFlatMultiLineString(ml)
+	if !f.MultiLineString().Similar(ml, 1e-12) {
+		t.Errorf("round trip: have %v, want %v", f.MultiLineString(), ml)
+	}
+	if l := f.Length(); l != 9 {
+		t.Errorf("length: have %g, want 9", l)
+	}
+	p := Point{X: 11, Y: 3}
+	if d, want := f.Distance(p), ml.Distance(p); d != want {
+		t.Errorf("distance: have %g, want %g", d, want)
+	}
+	if l, want := f.Locate(p), ml.Locate(p); l != want {
+		t.Errorf("locate: have %g, want %g", l, want)
+	}
+	if w := f.Within(&Bounds{Min: Point{X: 0, Y: 0}, Max: Point{X: 12, Y: 4}}); w != Inside {
+		t.Errorf("within: have %v, want Inside", w)
+	}
+	if !f.Similar(f, 0.1) || f.Similar(ml, 0.1) {
+		t.Error("similar: wrong result")
+	}
+}
+
+// TestFlatOperations checks that the Flat types give the same results as
+// the corresponding slice types in the operations that accept any Geom,
+// Linear or Polygonal value.
+func TestFlatOperations(t *testing.T) {
+	mp := MultiPolygon{
+		{
+			{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0}},
+			{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}, {X: 1, Y: 1}},
+		},
+		{{{X: 5, Y: 5}, {X: 5, Y: 7}, {X: 7, Y: 7}, {X: 7, Y: 5}, {X: 5, Y: 5}}},
+	}
+	ml := MultiLineString{
+		{{X: -1, Y: 3}, {X: 3, Y: 3.1}, {X: 6, Y: 6}},
+		{{X: 10, Y: 0}, {X: 10, Y: 2}, {X: 12, Y: 2}},
+	}
+	fp, fl := NewFlatMultiPolygon(mp), NewFlatMultiLineString(ml)
+	other := LineString{{X: 1, Y: -1}, {X: 1, Y: 8}, {X: 10, Y: 1}}
+
+	tests := []struct {
+		name string
+		op   func(p Polygonal, l Linear) interface{}
+	}{
+		{"LinearIntersection", func(p Polygonal, l Linear) interface{} {
+			points, lines := LinearIntersection(l, other)
+			return []interface{}{points, lines}
+		}},
+		{"LinearDifference", func(p Polygonal, l Linear) interface{} { return LinearDifference(l, p) }},
+		{"LinearUnion", func(p Polygonal, l Linear) interface{} { return LinearUnion(other, l) }},
+		{"MultiLineString.Intersection", func(p Polygonal, l Linear) interface{} {
+			points, lines := MultiLineString{other}.Intersection(l)
+			return []interface{}{points, lines}
+		}},
+		{"MultiLineString.Union", func(p Polygonal, l Linear) interface{} { return MultiLineString{other}.Union(l) }},
+		{"Interpolate", func(p Polygonal, l Linear) interface{} { return Interpolate(l, 9) }},
+		{"Locate", func(p Polygonal, l Linear) interface{} { return Locate(l, Point{X: 11, Y: 3}) }},
+		{"GeodesicLength", func(p Polygonal, l Linear) interface{} { return GeodesicLength(l, nil) }},
+		{"GeodesicArea", func(p Polygonal, l Linear) interface{} { return GeodesicArea(p, nil) }},
+		{"Smooth", func(p Polygonal, l Linear) interface{} {
+			return []Geom{Smooth(l, Chaikin, 2), Smooth(p, Chaikin, 2)}
+		}},
+		{"Densify", func(p Polygonal, l Linear) interface{} {
+			return []Geom{Densify(l, 0.5), Densify(p, 0.5)}
+		}},
+		{"Snap", func(p Polygonal, l Linear) interface{} {
+			return []Geom{Snap(l, p, 0.3), Snap(p, l, 0.3)}
+		}},
+		{"SnapToGrid", func(p Polygonal, l Linear) interface{} {
+			return []Geom{SnapToGrid(l, 0.5), SnapToGrid(p, 0.5)}
+		}},
+		{"SimplifyOptions.Simplify", func(p Polygonal, l Linear) interface{} {
+			o := SimplifyOptions{Algorithm: DouglasPeucker, Tolerance: 0.5}
+			return []Geom{o.Simplify(l), o.Simplify(p)}
+		}},
+		{"SimplifyCoverage", func(p Polygonal, l Linear) interface{} {
+			return SimplifyCoverage([]Polygonal{p}, 0.5)
+		}},
+		{"Distance", func(p Polygonal, l Linear) interface{} {
+			pa, pb := NearestPoints(l, p)
+			return []interface{}{Distance(l, p), pa, pb, IsWithinDistance(l, other, 0)}
+		}},
+		{"HausdorffDistance", func(p Polygonal, l Linear) interface{} {
+			d, pa, pb := HausdorffDistance(l, p, 0)
+			return []interface{}{d, pa, pb}
+		}},
+		{"FrechetDistance", func(p Polygonal, l Linear) interface{} {
+			d, pa, pb := FrechetDistance(l, other, 0)
+			return []interface{}{d, pa, pb}
+		}},
+		{"Split", func(p Polygonal, l Linear) interface{} { return Split(p, other) }},
+		{"RandomPoints", func(p Polygonal, l Linear) interface{} {
+			return RandomPoints(p, 5, rand.New(rand.NewSource(1)))
+		}},
+		{"Within", func(p Polygonal, l Linear) interface{} {
+			return []WithinStatus{Point{X: 1.5, Y: 3}.Within(p), other.Within(p), l.Within(p)}
+		}},
+		{"Polygon.Intersection", func(p Polygonal, l Linear) interface{} {
+			return Polygon{{{X: 3, Y: 3}, {X: 6, Y: 3}, {X: 6, Y: 6}, {X: 3, Y: 6}}}.Intersection(p)
+		}},
+	}
+	for _, test := range tests {
+		have, want := test.op(fp, fl), test.op(mp, ml)
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("%s: have %v, want %v", test.name, have, want)
+		}
+	}
+}
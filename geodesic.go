@@ -0,0 +1,680 @@
+package geom
+
+import (
+	"math"
+
+	"github.com/ctessum/geom/proj"
+)
+
+// Geodesic performs calculations of distances, azimuths and areas on the
+// surface of an ellipsoid, using the algorithms in:
+// C. F. F. Karney, Algorithms for geodesics. J. Geod. 87, 43–55 (2013).
+// The implementation is adapted from GeographicLib (MIT licensed),
+// https://geographiclib.sourceforge.io.
+//
+// Coordinates are expressed as longitude (X) and latitude (Y) in degrees,
+// distances are in the units of the ellipsoid semi-major axis, and
+// azimuths are in degrees clockwise from north.
+type Geodesic struct {
+	// A is the equatorial radius of the ellipsoid and F is its flattening.
+	A, F float64
+
+	f1, e2, ep2, n, b, c2, etol2 float64
+	a3x, c3x, c4x                []float64
+}
+
+// wgs84 is the geodesic calculator for the WGS84 ellipsoid.
+var wgs84 = NewGeodesic(6378137, 1/298.257223563)
+
+// WGS84 returns a geodesic calculator for the WGS84 ellipsoid.
+func WGS84() *Geodesic {
+	g := *wgs84
+	return &g
+}
+
+const (
+	geodTiny     = 0x1p-511 // sqrt of the smallest normalized float64
+	geodTol0     = 0x1p-52
+	geodTol1     = 200 * geodTol0
+	geodMaxit1   = 20
+	geodMaxit2   = geodMaxit1 + 53 + 10
+	geodNC3      = geodOrder
+	geodNC4      = geodOrder
+	geodNA3      = geodOrder
+	geodDegToRad = math.Pi / 180
+)
+
+var (
+	geodTol2    = math.Sqrt(geodTol0)
+	geodTolb    = geodTol0 * geodTol2
+	geodXthresh = 1000 * geodTol2
+)
+
+// NewGeodesic returns a geodesic calculator for the ellipsoid with
+// equatorial radius a and flattening f.
+func NewGeodesic(a, f float64) *Geodesic {
+	g := &Geodesic{A: a, F: f}
+	g.f1 = 1 - f
+	g.e2 = f * (2 - f)
+	g.ep2 = g.e2 / geodSq(g.f1)
+	g.n = f / (2 - f)
+	g.b = a * g.f1
+	var ratio float64
+	switch {
+	case g.e2 == 0:
+		ratio = 1
+	case g.e2 > 0:
+		ratio = math.Atanh(math.Sqrt(g.e2)) / math.Sqrt(g.e2)
+	default:
+		ratio = math.Atan(math.Sqrt(-g.e2)) / math.Sqrt(-g.e2)
+	}
+	g.c2 = (geodSq(a) + geodSq(g.b)*ratio) / 2
+	g.etol2 = 0.1 * geodTol2 /
+		math.Sqrt(math.Max(0.001, math.Abs(f))*math.Min(1, 1-f/2)/2)
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+	return g
+}
+
+// NewGeodesicSR returns a geodesic calculator for the ellipsoid of the
+// given spatial reference. If sr is nil, the WGS84 ellipsoid is used.
+func NewGeodesicSR(sr *proj.SR) *Geodesic {
+	if sr == nil {
+		return WGS84()
+	}
+	switch {
+	case !math.IsNaN(sr.B) && sr.B != 0:
+		return NewGeodesic(sr.A, (sr.A-sr.B)/sr.A)
+	case !math.IsNaN(sr.Es):
+		return NewGeodesic(sr.A, 1-math.Sqrt(1-sr.Es))
+	default:
+		return NewGeodesic(sr.A, 0)
+	}
+}
+
+// Inverse solves the inverse geodesic problem: it returns the distance
+// s12 between points p1 and p2 along the shortest path between them, and
+// the azimuths of that path at p1 and p2.
+func (g *Geodesic) Inverse(p1, p2 Point) (s12, azi1, azi2 float64) {
+	s12, salp1, calp1, salp2, calp2, _ := g.genInverse(p1.Y, p1.X, p2.Y, p2.X, false)
+	return s12, geodAtan2d(salp1, calp1), geodAtan2d(salp2, calp2)
+}
+
+// Direct solves the direct geodesic problem: it returns the point that is
+// distance s12 from p1 along the geodesic with azimuth azi1 at p1, and
+// the azimuth of the geodesic at that point.
+func (g *Geodesic) Direct(p1 Point, azi1, s12 float64) (p2 Point, azi2 float64) {
+	lat1 := geodLatFix(p1.Y)
+	azi1 = geodAngRound(azi1)
+	salp1, calp1 := geodSincosd(azi1)
+
+	sbet1, cbet1 := geodSincosd(geodAngRound(lat1))
+	sbet1 *= g.f1
+	sbet1, cbet1 = geodNorm(sbet1, cbet1)
+	cbet1 = math.Max(geodTiny, cbet1)
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+	ssig1 := sbet1
+	somg1 := salp0 * sbet1
+	csig1 := 1.
+	if sbet1 != 0 || calp1 != 0 {
+		csig1 = cbet1 * calp1
+	}
+	comg1 := csig1
+	ssig1, csig1 = geodNorm(ssig1, csig1)
+	k2 := geodSq(calp0) * g.ep2
+	eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+
+	A1m1 := geodA1m1f(eps)
+	C1a := make([]float64, geodOrder+1)
+	geodC1f(eps, C1a)
+	B11 := geodSinCosSeries(true, ssig1, csig1, C1a)
+	s, c := math.Sin(B11), math.Cos(B11)
+	stau1 := ssig1*c + csig1*s
+	ctau1 := csig1*c - ssig1*s
+	C1pa := make([]float64, geodOrder+1)
+	geodC1pf(eps, C1pa)
+	A3c := -g.F * salp0 * g.a3f(eps)
+	C3a := make([]float64, geodNC3)
+	g.c3f(eps, C3a)
+	B31 := geodSinCosSeries(true, ssig1, csig1, C3a)
+
+	tau12 := s12 / (g.b * (1 + A1m1))
+	s, c = math.Sin(tau12), math.Cos(tau12)
+	B12 := -geodSinCosSeries(true, stau1*c+ctau1*s, ctau1*c-stau1*s, C1pa)
+	sig12 := tau12 - (B12 - B11)
+	ssig12, csig12 := math.Sin(sig12), math.Cos(sig12)
+	if math.Abs(g.F) > 0.01 {
+		ssig2 := ssig1*csig12 + csig1*ssig12
+		csig2 := csig1*csig12 - ssig1*ssig12
+		B12 = geodSinCosSeries(true, ssig2, csig2, C1a)
+		serr := (1+A1m1)*(sig12+(B12-B11)) - s12/g.b
+		sig12 -= serr / math.Sqrt(1+k2*geodSq(ssig2))
+		ssig12, csig12 = math.Sin(sig12), math.Cos(sig12)
+	}
+	ssig2 := ssig1*csig12 + csig1*ssig12
+	csig2 := csig1*csig12 - ssig1*ssig12
+	sbet2 := calp0 * ssig2
+	cbet2 := math.Hypot(salp0, calp0*csig2)
+	if cbet2 == 0 {
+		cbet2 = geodTiny
+		csig2 = geodTiny
+	}
+	salp2 := salp0
+	calp2 := calp0 * csig2
+
+	somg2 := salp0 * ssig2
+	comg2 := csig2
+	omg12 := math.Atan2(somg2*comg1-comg2*somg1, comg2*comg1+somg2*somg1)
+	lam12 := omg12 + A3c*(sig12+(geodSinCosSeries(true, ssig2, csig2, C3a)-B31))
+	lon12 := lam12 / geodDegToRad
+	p2.X = geodAngNormalize(geodAngNormalize(p1.X) + geodAngNormalize(lon12))
+	p2.Y = geodAtan2d(sbet2, g.f1*cbet2)
+	return p2, geodAtan2d(salp2, calp2)
+}
+
+// genInverse solves the inverse problem, returning the distance, the
+// sines and cosines of the azimuths at each end, and (if area is true)
+// the area between the geodesic and the equator.
+func (g *Geodesic) genInverse(lat1, lon1, lat2, lon2 float64, area bool) (
+	s12, salp1, calp1, salp2, calp2, S12 float64) {
+
+	lon12, lon12s := geodAngDiff(lon1, lon2)
+	lonsign := 1.
+	if lon12 < 0 {
+		lonsign = -1
+	}
+	lon12 = lonsign * geodAngRound(lon12)
+	lon12s = geodAngRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * geodDegToRad
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = geodSincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = geodSincosd(lon12)
+	}
+	lat1 = geodAngRound(geodLatFix(lat1))
+	lat2 = geodAngRound(geodLatFix(lat2))
+	// Swap the points so that the first is further from the equator.
+	swapp := 1.
+	if math.Abs(lat1) < math.Abs(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := -1.
+	if lat1 < 0 {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := geodSincosd(lat1)
+	sbet1 *= g.f1
+	sbet1, cbet1 = geodNorm(sbet1, cbet1)
+	cbet1 = math.Max(geodTiny, cbet1)
+	sbet2, cbet2 := geodSincosd(lat2)
+	sbet2 *= g.f1
+	sbet2, cbet2 = geodNorm(sbet2, cbet2)
+	cbet2 = math.Max(geodTiny, cbet2)
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+	dn1 := math.Sqrt(1 + g.ep2*geodSq(sbet1))
+	dn2 := math.Sqrt(1 + g.ep2*geodSq(sbet2))
+
+	C1a := make([]float64, geodOrder+1)
+	C2a := make([]float64, geodOrder+1)
+	C3a := make([]float64, geodNC3)
+
+	var sig12, s12x, omg12, somg12, comg12 float64
+	somg12 = 2 // Indicates that somg12 and comg12 have not been set.
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// The endpoints are on a single meridian.
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1, 0
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		var m12x float64
+		s12x, m12x, _ = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, C1a, C2a)
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*geodTiny || (sig12 < geodTol0 && (s12x < 0 || m12x < 0)) {
+				sig12, s12x = 0, 0
+			}
+			s12x *= g.b
+		} else {
+			// The shortest path is not along the meridian.
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (g.F <= 0 || lon12s >= g.F*180) {
+		// The geodesic runs along the equator.
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = g.A * lam12
+		sig12 = lam12 / g.f1
+		omg12 = sig12
+	} else if !meridian {
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+			lam12, slam12, clam12, C1a, C2a)
+		if sig12 >= 0 {
+			// Short line.
+			s12x = sig12 * g.b * dnm
+			omg12 = lam12 / (g.f1 * dnm)
+		} else {
+			// Use Newton's method to find the azimuth at p1.
+			var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+			numit := 0
+			tripn, tripb := false, false
+			salp1a, calp1a := geodTiny, 1.
+			salp1b, calp1b := geodTiny, -1.
+			for ; numit < geodMaxit2; numit++ {
+				var v, dv float64
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv =
+					g.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1,
+						slam12, clam12, numit < geodMaxit1, C1a, C2a, C3a)
+				tol := geodTol0
+				if tripn {
+					tol *= 8
+				}
+				if tripb || !(math.Abs(v) >= tol) {
+					break
+				}
+				// Update the bracketing values.
+				if v > 0 && (numit > geodMaxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > geodMaxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				if numit < geodMaxit1 && dv > 0 {
+					dalp1 := -v / dv
+					sdalp1, cdalp1 := math.Sin(dalp1), math.Cos(dalp1)
+					nsalp1 := salp1*cdalp1 + calp1*sdalp1
+					if nsalp1 > 0 && math.Abs(dalp1) < math.Pi {
+						calp1 = calp1*cdalp1 - salp1*sdalp1
+						salp1 = nsalp1
+						salp1, calp1 = geodNorm(salp1, calp1)
+						tripn = math.Abs(v) <= 16*geodTol0
+						continue
+					}
+				}
+				// Newton's method failed, so fall back to bisection.
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = geodNorm(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodTolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < geodTolb
+			}
+			s12x, _, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, C1a, C2a)
+			s12x *= g.b
+			sdomg12, cdomg12 := math.Sin(domg12), math.Cos(domg12)
+			somg12 = slam12*cdomg12 - clam12*sdomg12
+			comg12 = clam12*cdomg12 + slam12*sdomg12
+		}
+	}
+	s12 = s12x
+
+	if area {
+		salp0 := salp1 * cbet1
+		calp0 := math.Hypot(calp1, salp1*sbet1)
+		if calp0 != 0 && salp0 != 0 {
+			ssig1, csig1 := geodNorm(sbet1, calp1*cbet1)
+			ssig2, csig2 := geodNorm(sbet2, calp2*cbet2)
+			k2 := geodSq(calp0) * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			A4 := geodSq(g.A) * calp0 * salp0 * g.e2
+			C4a := make([]float64, geodNC4)
+			g.c4f(eps, C4a)
+			B41 := geodSinCosSeries(false, ssig1, csig1, C4a)
+			B42 := geodSinCosSeries(false, ssig2, csig2, C4a)
+			S12 = A4 * (B42 - B41)
+		}
+		if !meridian && somg12 == 2 {
+			somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+		}
+		var alp12 float64
+		if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+			domg12 := 1 + comg12
+			dbet1 := 1 + cbet1
+			dbet2 := 1 + cbet2
+			alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1),
+				domg12*(sbet1*sbet2+dbet1*dbet2))
+		} else {
+			salp12 := salp2*calp1 - calp2*salp1
+			calp12 := calp2*calp1 + salp2*salp1
+			if salp12 == 0 && calp12 < 0 {
+				salp12 = geodTiny * calp1
+				calp12 = -1
+			}
+			alp12 = math.Atan2(salp12, calp12)
+		}
+		S12 += g.c2 * alp12
+		S12 *= swapp * lonsign * latsign
+		S12 += 0
+	}
+
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+	return s12, salp1, calp1, salp2, calp2, S12
+}
+
+// lengths returns the distance s12b and reduced length m12b (both divided
+// by b) along a geodesic with parameter eps and arc length sig12, and the
+// value of m0.
+func (g *Geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64,
+	C1a, C2a []float64) (s12b, m12b, m0 float64) {
+	A1 := geodA1m1f(eps)
+	geodC1f(eps, C1a)
+	A2 := geodA2m1f(eps)
+	geodC2f(eps, C2a)
+	m0 = A1 - A2
+	A2++
+	A1++
+	B1 := geodSinCosSeries(true, ssig2, csig2, C1a) - geodSinCosSeries(true, ssig1, csig1, C1a)
+	s12b = A1 * (sig12 + B1)
+	B2 := geodSinCosSeries(true, ssig2, csig2, C2a) - geodSinCosSeries(true, ssig1, csig1, C2a)
+	J12 := m0*sig12 + (A1*B1 - A2*B2)
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*J12
+	return s12b, m12b, m0
+}
+
+// inverseStart returns a starting point for Newton's method in
+// genInverse. If the points are close enough together, it instead
+// returns the solution directly with sig12 >= 0.
+func (g *Geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+	lam12, slam12, clam12 float64, C1a, C2a []float64) (
+	sig12, salp1, calp1, salp2, calp2, dnm float64) {
+
+	sig12 = -1
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := geodSq(sbet1 + sbet2)
+		sbetm2 /= sbetm2 + geodSq(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*geodSq(somg12)/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*geodSq(somg12)/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < g.etol2 {
+		// The points are very close together.
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*geodSq(somg12)/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = geodNorm(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(g.n) >= 0.1 || csig12 >= 0 ||
+		ssig12 >= 6*math.Abs(g.n)*math.Pi*geodSq(cbet1) {
+		// Nothing to do: the zeroth order spherical approximation is
+		// good enough.
+	} else {
+		// The points are nearly antipodal.
+		lam12x := math.Atan2(-slam12, -clam12)
+		var x, y, lamscale, betscale float64
+		if g.F >= 0 {
+			k2 := geodSq(sbet1) * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			lamscale = g.F * cbet1 * g.a3f(eps) * math.Pi
+			betscale = lamscale * cbet1
+			x = lam12x / lamscale
+			y = sbet12a / betscale
+		} else {
+			cbet12a := cbet2*cbet1 - sbet2*sbet1
+			bet12a := math.Atan2(sbet12a, cbet12a)
+			_, m12b, m0 := g.lengths(g.n, math.Pi+bet12a, sbet1, -cbet1, dn1, sbet2, cbet2, dn2, C1a, C2a)
+			x = -1 + m12b/(cbet1*cbet2*m0*math.Pi)
+			if x < -0.01 {
+				betscale = sbet12a / x
+			} else {
+				betscale = -g.F * geodSq(cbet1) * math.Pi
+			}
+			lamscale = betscale / cbet1
+			y = lam12x / lamscale
+		}
+		if y > -geodTol1 && x > -1-geodXthresh {
+			if g.F >= 0 {
+				salp1 = math.Min(1, -x)
+				calp1 = -math.Sqrt(1 - geodSq(salp1))
+			} else {
+				if x > -geodTol1 {
+					calp1 = math.Max(0, x)
+				} else {
+					calp1 = math.Max(-1, x)
+				}
+				salp1 = math.Sqrt(1 - geodSq(calp1))
+			}
+		} else {
+			k := geodAstroid(x, y)
+			var omg12a float64
+			if g.F >= 0 {
+				omg12a = lamscale * (-x * k / (1 + k))
+			} else {
+				omg12a = lamscale * (-y * (1 + k) / k)
+			}
+			somg12, comg12 = math.Sin(omg12a), -math.Cos(omg12a)
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*geodSq(somg12)/(1-comg12)
+		}
+	}
+	if !(salp1 <= 0) {
+		salp1, calp1 = geodNorm(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+// lambda12 returns the longitude difference lam12 (less the target
+// longitude difference) for a geodesic leaving p1 with the given azimuth,
+// along with the intermediate values needed by genInverse and, if diffp
+// is true, the derivative of lam12 with respect to the azimuth.
+func (g *Geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1,
+	slam120, clam120 float64, diffp bool, C1a, C2a, C3a []float64) (
+	lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64) {
+
+	if sbet1 == 0 && calp1 == 0 {
+		// Break degeneracy of equatorial line.
+		calp1 = -geodTiny
+	}
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = geodNorm(ssig1, csig1)
+
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var t float64
+		if cbet1 < -sbet1 {
+			t = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			t = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt(geodSq(calp1*cbet1)+t) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = geodNorm(ssig2, csig2)
+
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := geodSq(calp0) * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, C3a)
+	B312 := geodSinCosSeries(true, ssig2, csig2, C3a) - geodSinCosSeries(true, ssig1, csig1, C3a)
+	domg12 = -g.F * g.a3f(eps) * salp0 * (sig12 + B312)
+	lam12 = eta + domg12
+
+	dlam12 = math.NaN()
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, C1a, C2a)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	}
+	return
+}
+
+func (g *Geodesic) a3f(eps float64) float64 {
+	return geodPolyval(geodNA3-1, g.a3x, 0, eps)
+}
+
+func (g *Geodesic) c3f(eps float64, c []float64) {
+	mult := 1.
+	o := 0
+	for l := 1; l < geodNC3; l++ {
+		m := geodNC3 - l - 1
+		mult *= eps
+		c[l] = mult * geodPolyval(m, g.c3x, o, eps)
+		o += m + 1
+	}
+}
+
+func (g *Geodesic) c4f(eps float64, c []float64) {
+	mult := 1.
+	o := 0
+	for l := 0; l < geodNC4; l++ {
+		m := geodNC4 - l - 1
+		c[l] = mult * geodPolyval(m, g.c4x, o, eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+func (g *Geodesic) a3coeff() {
+	coeff := []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	o, k := 0, 0
+	g.a3x = make([]float64, geodNA3)
+	for j := geodNA3 - 1; j >= 0; j-- {
+		m := geodNA3 - j - 1
+		if j < m {
+			m = j
+		}
+		g.a3x[k] = geodPolyval(m, coeff, o, g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+func (g *Geodesic) c3coeff() {
+	coeff := []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+	o, k := 0, 0
+	g.c3x = make([]float64, (geodNC3*(geodNC3-1))/2)
+	for l := 1; l < geodNC3; l++ {
+		for j := geodNC3 - 1; j >= l; j-- {
+			m := geodNC3 - j - 1
+			if j < m {
+				m = j
+			}
+			g.c3x[k] = geodPolyval(m, coeff, o, g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (g *Geodesic) c4coeff() {
+	coeff := []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+	o, k := 0, 0
+	g.c4x = make([]float64, (geodNC4*(geodNC4+1))/2)
+	for l := 0; l < geodNC4; l++ {
+		for j := geodNC4 - 1; j >= l; j-- {
+			m := geodNC4 - j - 1
+			g.c4x[k] = geodPolyval(m, coeff, o, g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
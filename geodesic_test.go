@@ -0,0 +1,116 @@
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ctessum/geom/proj"
+)
+
+func TestGeodesicInverse(t *testing.T) {
+	// JFK to LHR, from the GeographicLib documentation.
+	s12, azi1, azi2 := WGS84().Inverse(Point{X: -73.8, Y: 40.6}, Point{X: -0.5, Y: 51.6})
+	if math.Abs(s12-5551759.400) > 1.e-3 {
+		t.Errorf("s12: have %.4f, want 5551759.400", s12)
+	}
+	if math.Abs(azi1-51.198883) > 1.e-6 {
+		t.Errorf("azi1: have %.7f, want 51.198883", azi1)
+	}
+	if math.Abs(azi2-107.821777) > 1.e-6 {
+		t.Errorf("azi2: have %.7f, want 107.821777", azi2)
+	}
+
+	// Nearly antipodal points.
+	s12, _, _ = WGS84().Inverse(Point{X: 0, Y: 0}, Point{X: 179.5, Y: 0.5})
+	if math.Abs(s12-19936288.579) > 1.e-3 {
+		t.Errorf("antipodal s12: have %.4f, want 19936288.579", s12)
+	}
+}
+
+func TestGeodesicDirect(t *testing.T) {
+	p1 := Point{X: -73.8, Y: 40.6}
+	for _, azi1 := range []float64{-170, -45, 0, 30, 90, 135, 180} {
+		for _, s12 := range []float64{10, 1.e5, 5.e6, 1.5e7} {
+			p2, azi2 := WGS84().Direct(p1, azi1, s12)
+			s, a1, a2 := WGS84().Inverse(p1, p2)
+			if math.Abs(s-s12) > 1.e-6 {
+				t.Errorf("azi1=%g, s12=%g: inverse distance %g", azi1, s12, s)
+			}
+			if math.Abs(geodAngNormalize(a1-azi1)) > 1.e-7 || math.Abs(geodAngNormalize(a2-azi2)) > 1.e-7 {
+				t.Errorf("azi1=%g, s12=%g: azimuths (%g, %g) != (%g, %g)", azi1, s12, a1, a2, azi1, azi2)
+			}
+		}
+	}
+}
+
+func TestGeodesicArea(t *testing.T) {
+	// The area between the equator and latitude phi over one degree of
+	// longitude on an ellipsoid.
+	zone := func(g *Geodesic, phi float64) float64 {
+		e := math.Sqrt(g.e2)
+		s := math.Sin(phi * math.Pi / 180)
+		b := g.A * (1 - g.F)
+		return math.Pi * b * b * (s/(1-g.e2*s*s) + math.Log((1+e*s)/(1-e*s))/(2*e)) / 360
+	}
+	cell := Polygon{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}}}
+	have := GeodesicArea(cell, nil)
+	// Value from GeographicLib.
+	if want := 12308778361.469; math.Abs(have-want) > 1.e-2 {
+		t.Errorf("cell: have %.3f, want %.3f", have, want)
+	}
+	// The geodesic edge at 1° latitude bulges slightly towards the pole, so
+	// the cell is slightly larger than the area bounded by the parallel.
+	if want := zone(WGS84(), 1); math.Abs(have-want)/want > 5.e-5 {
+		t.Errorf("cell: have %g, want %g", have, want)
+	}
+
+	// Holes are subtracted, and winding order does not matter.
+	big := Path{{X: -1, Y: -1}, {X: -1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: -1}, {X: -1, Y: -1}}
+	withHole := Polygon{big, cell[0]}
+	wantHole := GeodesicArea(Polygon{big}, nil) - have
+	if h := GeodesicArea(withHole, nil); math.Abs(h-wantHole) > 1.e-3 {
+		t.Errorf("hole: have %g, want %g", h, wantHole)
+	}
+
+	// A polygon around the north pole should have the same area as the
+	// triangles that it can be divided into.
+	pole := Polygon{{{X: 0, Y: 80}, {X: 90, Y: 80}, {X: 180, Y: 80}, {X: -90, Y: 80}, {X: 0, Y: 80}}}
+	var triangles float64
+	for i := 0; i < 4; i++ {
+		triangles += GeodesicArea(Polygon{{pole[0][i], pole[0][i+1], {X: 0, Y: 90}}}, nil)
+	}
+	if a := GeodesicArea(pole, nil); math.Abs(a-triangles) > 1 {
+		t.Errorf("pole: have %g, want %g", a, triangles)
+	}
+}
+
+func TestGeodesicSR(t *testing.T) {
+	sr, err := proj.Parse("+proj=longlat +ellps=GRS80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGeodesicSR(sr)
+	if math.Abs(g.A-6378137) > 1.e-9 || math.Abs(1/g.F-298.257222101) > 1.e-6 {
+		t.Errorf("have a=%g, 1/f=%g", g.A, 1/g.F)
+	}
+	l := LineString{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}
+	if d := GeodesicLength(l, sr); math.Abs(d-111319.491-110574.389) > 1 {
+		t.Errorf("length: have %g", d)
+	}
+}
+
+func TestGeodesicBuffer(t *testing.T) {
+	p := Point{X: 10, Y: 50}
+	buf := p.GeodesicBuffer(1000, 16, nil)
+	if len(buf[0]) != 16 {
+		t.Fatalf("have %d points", len(buf[0]))
+	}
+	for _, pp := range buf[0] {
+		if d := GeodesicDistance(p, pp, nil); math.Abs(d-1000) > 1.e-6 {
+			t.Errorf("distance: have %g, want 1000", d)
+		}
+	}
+	if buf[0][0].X <= p.X || math.Abs(buf[0][0].Y-p.Y) > 1.e-4 {
+		t.Errorf("first point should be to the east: %v", buf[0][0])
+	}
+}
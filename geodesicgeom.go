@@ -0,0 +1,148 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ctessum/geom/proj"
+)
+
+// GeodesicDistance returns the length of the shortest path between p1 and p2
+// along the surface of the ellipsoid of sr, where p1 and p2 are
+// longitude-latitude coordinates in degrees. If sr is nil, the WGS84
+// ellipsoid is used.
+func GeodesicDistance(p1, p2 Point, sr *proj.SR) float64 {
+	return NewGeodesicSR(sr).Distance(p1, p2)
+}
+
+// GeodesicLength returns the length of l along the surface of the
+// ellipsoid of sr, where the vertices of l are longitude-latitude coordinates
+// in degrees and are connected by geodesics. If sr is nil, the WGS84
+// ellipsoid is used.
+func GeodesicLength(l Linear, sr *proj.SR) float64 {
+	return NewGeodesicSR(sr).Length(l)
+}
+
+// GeodesicArea returns the area of p on the surface of the ellipsoid of sr,
+// where the vertices of p are longitude-latitude coordinates in degrees and
+// are connected by geodesics. If sr is nil, the WGS84 ellipsoid is used.
+// As with Polygon.Area, holes are subtracted regardless of their winding
+// order.
+func GeodesicArea(p Polygonal, sr *proj.SR) float64 {
+	return NewGeodesicSR(sr).Area(p)
+}
+
+// GeodesicBuffer returns a circle on the ellipsoid of sr made up of the
+// points that are the given geodesic distance from p, where p is a
+// longitude-latitude coordinate in degrees. The circle is represented
+// as a polygon with the specified number of segments. If sr is nil, the WGS84
+// ellipsoid is used.
+func (p Point) GeodesicBuffer(radius float64, segments int, sr *proj.SR) Polygon {
+	return NewGeodesicSR(sr).Buffer(p, radius, segments)
+}
+
+// Distance returns the length of the shortest path between p1 and p2.
+func (g *Geodesic) Distance(p1, p2 Point) float64 {
+	s12, _, _ := g.Inverse(p1, p2)
+	return s12
+}
+
+// Length returns the length of l, where its vertices are connected by
+// geodesics.
+func (g *Geodesic) Length(l Linear) float64 {
+	var length float64
+	for _, ls := range linearLines(l) {
+		for i := 0; i < len(ls)-1; i++ {
+			length += g.Distance(ls[i], ls[i+1])
+		}
+	}
+	return length
+}
+
+// Area returns the area of p, where its vertices are connected by
+// geodesics.
+func (g *Geodesic) Area(p Polygonal) float64 {
+	var a float64
+	for _, poly := range p.Polygons() {
+		bounds := poly.ringBounds()
+		var pa float64
+		for i, r := range poly {
+			ra := math.Abs(g.ringArea(r))
+			if area(r, i, poly, bounds) < 0 {
+				pa -= ra // This is a hole.
+			} else {
+				pa += ra
+			}
+		}
+		a += math.Abs(pa)
+	}
+	return a
+}
+
+// ringArea returns the area of ring r, which is positive if r
+// is wound counter-clockwise.
+func (g *Geodesic) ringArea(r Path) float64 {
+	var a float64
+	var crossings int
+	for i := range r {
+		p1, p2 := r[i], r[(i+1)%len(r)]
+		if p1.Equals(p2) {
+			continue
+		}
+		_, _, _, _, _, S12 := g.genInverse(p1.Y, p1.X, p2.Y, p2.X, true)
+		a += S12
+		crossings += geodTransit(p1.X, p2.X)
+	}
+	area0 := 4 * math.Pi * g.c2
+	a = math.Remainder(a, area0)
+	if crossings&1 != 0 {
+		if a < 0 {
+			a += area0 / 2
+		} else {
+			a -= area0 / 2
+		}
+	}
+	a = -a
+	if a > area0/2 {
+		a -= area0
+	} else if a <= -area0/2 {
+		a += area0
+	}
+	return a
+}
+
+// geodTransit returns 1 or -1 if the edge from lon1 to lon2 crosses the
+// prime meridian in the eastward or westward direction, respectively, and
+// 0 otherwise.
+func geodTransit(lon1, lon2 float64) int {
+	lon12, _ := geodAngDiff(lon1, lon2)
+	lon1 = geodAngNormalize(lon1)
+	lon2 = geodAngNormalize(lon2)
+	if lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)) {
+		return 1
+	}
+	if lon12 < 0 && lon1 >= 0 && lon2 < 0 {
+		return -1
+	}
+	return 0
+}
+
+// Buffer returns a circle made up of the points that are the given
+// distance from p. The circle is represented as a polygon with the
+// specified number of segments.
+func (g *Geodesic) Buffer(p Point, radius float64, segments int) Polygon {
+	if segments < 3 {
+		panic(fmt.Errorf("geom: invalid number of segments %d", segments))
+	}
+	if radius < 0 {
+		panic(fmt.Errorf("geom: invalid radius %g", radius))
+	}
+	dTheta := 360 / float64(segments)
+	o := make(Polygon, 1)
+	o[0] = make([]Point, segments)
+	for i := 0; i < segments; i++ {
+		// Start to the east and proceed counter-clockwise, as in Point.Buffer.
+		o[0][i], _ = g.Direct(p, 90-float64(i)*dTheta, radius)
+	}
+	return o
+}
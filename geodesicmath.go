@@ -0,0 +1,274 @@
+package geom
+
+import "math"
+
+// The functions in this file are helpers for the geodesic calculations,
+// adapted from GeographicLib by Charles Karney (MIT licensed).
+// See https://geographiclib.sourceforge.io.
+
+// geodSq returns x squared.
+func geodSq(x float64) float64 { return x * x }
+
+// geodSum returns the error-free sum of u and v as s + t.
+func geodSum(u, v float64) (s, t float64) {
+	s = u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	t = -(up + vpp)
+	return s, t
+}
+
+// geodPolyval evaluates the polynomial of order n with coefficients
+// p[s:s+n+1] (highest order first) at x.
+func geodPolyval(n int, p []float64, s int, x float64) float64 {
+	var y float64
+	if n >= 0 {
+		y = p[s]
+	}
+	for n > 0 {
+		n--
+		s++
+		y = y*x + p[s]
+	}
+	return y
+}
+
+// geodAngRound rounds angle x so that small values are represented
+// exactly, which reduces the chance of problems near the poles
+// and the equator.
+func geodAngRound(x float64) float64 {
+	const z = 1. / 16
+	y := math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+	return math.Copysign(y, x)
+}
+
+// geodAngNormalize reduces angle x (in degrees) to the range (-180, 180].
+func geodAngNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if y == -180 {
+		return 180
+	}
+	return y
+}
+
+// geodLatFix returns NaN for latitudes outside of [-90, 90].
+func geodLatFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// geodAngDiff returns the exact difference y - x of two angles as
+// d + e, with d reduced to the range [-180, 180].
+func geodAngDiff(x, y float64) (d, e float64) {
+	d, t := geodSum(geodAngNormalize(-x), geodAngNormalize(y))
+	d = geodAngNormalize(d)
+	if d == 180 && t > 0 {
+		d = -180
+	}
+	return geodSum(d, t)
+}
+
+// geodSincosd returns the sine and cosine of x (in degrees), exactly
+// for multiples of 90 degrees.
+func geodSincosd(x float64) (s, c float64) {
+	r := math.Mod(x, 360)
+	var q int
+	if !math.IsNaN(r) {
+		q = int(math.Round(r / 90))
+	}
+	r -= 90 * float64(q)
+	r *= math.Pi / 180
+	s, c = math.Sin(r), math.Cos(r)
+	switch ((q % 4) + 4) % 4 {
+	case 1:
+		s, c = c, -s
+	case 2:
+		s, c = -s, -c
+	case 3:
+		s, c = -c, s
+	}
+	return s, c
+}
+
+// geodAtan2d returns atan2(y, x) in degrees, exactly for multiples
+// of 45 degrees.
+func geodAtan2d(y, x float64) float64 {
+	var q int
+	if math.Abs(y) > math.Abs(x) {
+		q = 2
+		x, y = y, x
+	}
+	if x < 0 {
+		q++
+		x = -x
+	}
+	ang := math.Atan2(y, x) * 180 / math.Pi
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
+
+// geodNorm scales x and y so that x² + y² = 1.
+func geodNorm(x, y float64) (float64, float64) {
+	r := math.Hypot(x, y)
+	return x / r, y / r
+}
+
+// geodSinCosSeries evaluates the trigonometric series
+// sum(c[i] * sin(2*i*x)) (when sinp is true) or
+// sum(c[i] * cos((2*i+1)*x)) (when sinp is false) using Clenshaw summation.
+func geodSinCosSeries(sinp bool, sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	n := k
+	if sinp {
+		n--
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	n /= 2
+	for n > 0 {
+		n--
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+	return cosx * (y0 - y1)
+}
+
+// geodAstroid solves the astroid equation
+// k⁴ + 2k³ - (x² + y² - 1)k² - 2y²k - y² = 0 for the positive root k.
+func geodAstroid(x, y float64) float64 {
+	p := geodSq(x)
+	q := geodSq(y)
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	S := p * q / 4
+	r2 := geodSq(r)
+	r3 := r * r2
+	disc := S * (S + 2*r3)
+	u := r
+	if disc >= 0 {
+		T3 := S + r3
+		if T3 < 0 {
+			T3 -= math.Sqrt(disc)
+		} else {
+			T3 += math.Sqrt(disc)
+		}
+		T := math.Cbrt(T3)
+		u += T
+		if T != 0 {
+			u += r2 / T
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(geodSq(u) + q)
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+geodSq(w)) + w)
+}
+
+const geodOrder = 6
+
+// geodA1m1f returns the scale factor A1-1 for the distance integral.
+func geodA1m1f(eps float64) float64 {
+	coeff := []float64{1, 4, 64, 0, 256}
+	m := geodOrder / 2
+	t := geodPolyval(m, coeff, 0, geodSq(eps)) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// geodC1f sets c[1:] to the coefficients C1 of the distance integral.
+func geodC1f(eps float64, c []float64) {
+	coeff := []float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	geodSeriesCoeffs(eps, coeff, c)
+}
+
+// geodC1pf sets c[1:] to the coefficients C1' of the inverse of the
+// distance integral.
+func geodC1pf(eps float64, c []float64) {
+	coeff := []float64{
+		205, -432, 768, 1536,
+		4005, -4736, 3840, 12288,
+		-225, 116, 384,
+		-7173, 2695, 7680,
+		3467, 7680,
+		38081, 61440,
+	}
+	geodSeriesCoeffs(eps, coeff, c)
+}
+
+// geodA2m1f returns the scale factor A2-1 for the reduced length integral.
+func geodA2m1f(eps float64) float64 {
+	coeff := []float64{-11, -28, -192, 0, 256}
+	m := geodOrder / 2
+	t := geodPolyval(m, coeff, 0, geodSq(eps)) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// geodC2f sets c[1:] to the coefficients C2 of the reduced length integral.
+func geodC2f(eps float64, c []float64) {
+	coeff := []float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	geodSeriesCoeffs(eps, coeff, c)
+}
+
+// geodSeriesCoeffs evaluates the series coefficients c[1:] in eps from
+// the polynomial coefficients in coeff.
+func geodSeriesCoeffs(eps float64, coeff, c []float64) {
+	eps2 := geodSq(eps)
+	d := eps
+	o := 0
+	for l := 1; l <= geodOrder; l++ {
+		m := (geodOrder - l) / 2
+		c[l] = d * geodPolyval(m, coeff, o, eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
@@ -0,0 +1,389 @@
+package geom
+
+import (
+	"math"
+
+	"github.com/ctessum/geom/proj"
+)
+
+// PointM is a holder for 2D coordinates X and Y along with a measure M,
+// such as the distance along a route from a reference point.
+type PointM struct {
+	X, Y, M float64
+}
+
+// XY returns the X and Y coordinates of p.
+func (p PointM) XY() Point { return Point{X: p.X, Y: p.Y} }
+
+// LineStringM is a number of points with measures that make up a path or
+// line. The measures are interpolated linearly between the points.
+type LineStringM []PointM
+
+// MultiLineStringM is a holder for multiple related LineStringMs, such as
+// the parts of a route.
+type MultiLineStringM []LineStringM
+
+func pointsMXY(points []PointM) []Point {
+	o := make([]Point, len(points))
+	for i, p := range points {
+		o[i] = p.XY()
+	}
+	return o
+}
+
+func pointsMSimilar(p1s, p2s []PointM, e float64) bool {
+	if len(p1s) != len(p2s) {
+		return false
+	}
+	for i := range p1s {
+		if !similar(p1s[i].X, p2s[i].X, e) || !similar(p1s[i].Y, p2s[i].Y, e) ||
+			!similar(p1s[i].M, p2s[i].M, e) {
+			return false
+		}
+	}
+	return true
+}
+
+// interpolatePointM returns the point fraction f of the way from a to b.
+func interpolatePointM(a, b PointM, f float64) PointM {
+	return PointM{
+		X: a.X + (b.X-a.X)*f,
+		Y: a.Y + (b.Y-a.Y)*f,
+		M: a.M + (b.M-a.M)*f,
+	}
+}
+
+// Bounds gives the rectangular extents of the LineStringM.
+func (l LineStringM) Bounds() *Bounds {
+	b := NewBounds()
+	for _, p := range l {
+		b.extendPoint(p.XY())
+	}
+	return b
+}
+
+// Similar determines whether two geometries are similar within tolerance.
+// The measures must also be similar.
+func (l LineStringM) Similar(g Geom, tolerance float64) bool {
+	l2, ok := g.(LineStringM)
+	return ok && pointsMSimilar(l, l2, tolerance)
+}
+
+// Transform shifts the coordinates of l according to t. The measures are
+// unchanged.
+func (l LineStringM) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return l, nil
+	}
+	l2 := make(LineStringM, len(l))
+	var err error
+	for i, p := range l {
+		l2[i].M = p.M
+		l2[i].X, l2[i].Y, err = t(p.X, p.Y)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return l2, nil
+}
+
+// Force2D returns l without its measures.
+func (l LineStringM) Force2D() Geom { return LineString(pointsMXY(l)) }
+
+// Length returns the length of l.
+func (l LineStringM) Length() float64 { return LineString(pointsMXY(l)).Length() }
+
+// Len returns the number of points in the receiver.
+func (l LineStringM) Len() int { return len(l) }
+
+// Points returns an iterator for the points in the receiver.
+func (l LineStringM) Points() func() Point {
+	var i int
+	return func() Point {
+		i++
+		return l[i-1].XY()
+	}
+}
+
+// MRange returns the smallest and largest measures in l. If l is empty,
+// min will be +Inf and max will be -Inf.
+func (l LineStringM) MRange() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, p := range l {
+		min = math.Min(min, p.M)
+		max = math.Max(max, p.M)
+	}
+	return min, max
+}
+
+// measurePosition returns the index of the first segment of l along which
+// the measure reaches m, and the fraction of the way along the segment
+// where it does. ok is false if m is not reached.
+func (l LineStringM) measurePosition(m float64) (i int, f float64, ok bool) {
+	if len(l) == 1 && l[0].M == m {
+		return 0, 0, true
+	}
+	for i = 0; i < len(l)-1; i++ {
+		a, b := l[i].M, l[i+1].M
+		if m < math.Min(a, b) || m > math.Max(a, b) {
+			continue
+		}
+		if a == b {
+			return i, 0, true
+		}
+		return i, (m - a) / (b - a), true
+	}
+	return 0, 0, false
+}
+
+// at returns the point fraction f of the way along segment i of l.
+func (l LineStringM) at(i int, f float64) PointM {
+	if f == 0 || i == len(l)-1 {
+		return l[i]
+	}
+	return interpolatePointM(l[i], l[i+1], f)
+}
+
+// LocateMeasure returns the first point along l where the measure is m,
+// interpolating between the measures of the points in l. ok is false if
+// the measure of l never reaches m.
+func (l LineStringM) LocateMeasure(m float64) (p Point, ok bool) {
+	i, f, ok := l.measurePosition(m)
+	if !ok {
+		return nanPoint, false
+	}
+	return l.at(i, f).XY(), true
+}
+
+// SubstringMeasure returns the part of l between the first points where
+// the measure is start and end. Measures outside of the range of l are
+// clamped to it, and nil is returned if the range from start to end does
+// not overlap the range of l at all. The returned line runs from start to
+// end, so it runs in the opposite direction from l if the measures of l
+// increase and end is less than start.
+func (l LineStringM) SubstringMeasure(start, end float64) LineStringM {
+	min, max := l.MRange()
+	if math.Max(start, end) < min || math.Min(start, end) > max {
+		return nil
+	}
+	start = math.Max(min, math.Min(start, max))
+	end = math.Max(min, math.Min(end, max))
+	i0, f0, _ := l.measurePosition(start)
+	i1, f1, _ := l.measurePosition(end)
+	reverse := i1 < i0 || (i1 == i0 && f1 < f0)
+	if reverse {
+		i0, f0, i1, f1 = i1, f1, i0, f0
+	}
+	o := LineStringM{l.at(i0, f0)}
+	for i := i0 + 1; i <= i1; i++ {
+		if l[i] != o[len(o)-1] {
+			o = append(o, l[i])
+		}
+	}
+	if p := l.at(i1, f1); p != o[len(o)-1] {
+		o = append(o, p)
+	}
+	if reverse {
+		for i, j := 0, len(o)-1; i < j; i, j = i+1, j-1 {
+			o[i], o[j] = o[j], o[i]
+		}
+	}
+	return o
+}
+
+// Measure returns the measure at the point on l that is closest to p.
+func (l LineStringM) Measure(p Point) float64 {
+	m, _ := l.measure(p)
+	return m
+}
+
+// measure returns the measure at the point on l that is closest to p,
+// along with the distance from p to that point.
+func (l LineStringM) measure(p Point) (m, dist float64) {
+	switch len(l) {
+	case 0:
+		return math.NaN(), math.Inf(1)
+	case 1:
+		return l[0].M, d(p, l[0].XY())
+	}
+	dist = math.Inf(1)
+	for i := 0; i < len(l)-1; i++ {
+		a, b := l[i].XY(), l[i+1].XY()
+		f := projectSegment(p, a, b)
+		if dd := d(p, interpolateSegment(a, b, f)); dd < dist {
+			dist = dd
+			m = l[i].M + (l[i+1].M-l[i].M)*f
+		}
+	}
+	return m, dist
+}
+
+// CalibrateMeasures returns a copy of l with its measures recalculated in
+// proportion to the distance along l, so that the measure is start at the
+// first point of l and end at the last point.
+func (l LineStringM) CalibrateMeasures(start, end float64) LineStringM {
+	return MultiLineStringM{l}.CalibrateMeasures(start, end)[0]
+}
+
+// Bounds gives the rectangular extents of the MultiLineStringM.
+func (ml MultiLineStringM) Bounds() *Bounds {
+	b := NewBounds()
+	for _, l := range ml {
+		for _, p := range l {
+			b.extendPoint(p.XY())
+		}
+	}
+	return b
+}
+
+// Similar determines whether two geometries are similar within tolerance.
+// The LineStringMs must be in the same order, and the measures must also
+// be similar.
+func (ml MultiLineStringM) Similar(g Geom, tolerance float64) bool {
+	ml2, ok := g.(MultiLineStringM)
+	if !ok || len(ml) != len(ml2) {
+		return false
+	}
+	for i := range ml {
+		if !pointsMSimilar(ml[i], ml2[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform shifts the coordinates of ml according to t. The measures are
+// unchanged.
+func (ml MultiLineStringM) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return ml, nil
+	}
+	ml2 := make(MultiLineStringM, len(ml))
+	for i, l := range ml {
+		g, err := l.Transform(t)
+		if err != nil {
+			return nil, err
+		}
+		ml2[i] = g.(LineStringM)
+	}
+	return ml2, nil
+}
+
+// Force2D returns ml without its measures.
+func (ml MultiLineStringM) Force2D() Geom {
+	o := make(MultiLineString, len(ml))
+	for i, l := range ml {
+		o[i] = pointsMXY(l)
+	}
+	return o
+}
+
+// Length returns the length of ml.
+func (ml MultiLineStringM) Length() float64 {
+	length := 0.
+	for _, l := range ml {
+		length += l.Length()
+	}
+	return length
+}
+
+// Len returns the number of points in the receiver.
+func (ml MultiLineStringM) Len() int {
+	var i int
+	for _, l := range ml {
+		i += len(l)
+	}
+	return i
+}
+
+// Points returns an iterator for the points in the receiver.
+func (ml MultiLineStringM) Points() func() Point {
+	var i, j int
+	return func() Point {
+		for j >= len(ml[i]) {
+			i++
+			j = 0
+		}
+		j++
+		return ml[i][j-1].XY()
+	}
+}
+
+// MRange returns the smallest and largest measures in ml. If ml is empty,
+// min will be +Inf and max will be -Inf.
+func (ml MultiLineStringM) MRange() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, l := range ml {
+		lMin, lMax := l.MRange()
+		min = math.Min(min, lMin)
+		max = math.Max(max, lMax)
+	}
+	return min, max
+}
+
+// LocateMeasure returns the first point along the first LineStringM in ml
+// where the measure is m. ok is false if no part of ml has measure m.
+func (ml MultiLineStringM) LocateMeasure(m float64) (p Point, ok bool) {
+	for _, l := range ml {
+		if p, ok = l.LocateMeasure(m); ok {
+			return p, true
+		}
+	}
+	return nanPoint, false
+}
+
+// SubstringMeasure returns the parts of the LineStringMs in ml that are
+// between measures start and end, as calculated by
+// LineStringM.SubstringMeasure. LineStringMs whose measures are entirely
+// outside of the range are omitted.
+func (ml MultiLineStringM) SubstringMeasure(start, end float64) MultiLineStringM {
+	var o MultiLineStringM
+	for _, l := range ml {
+		if s := l.SubstringMeasure(start, end); s != nil {
+			o = append(o, s)
+		}
+	}
+	return o
+}
+
+// Measure returns the measure at the point on ml that is closest to p.
+func (ml MultiLineStringM) Measure(p Point) float64 {
+	m, minDist := math.NaN(), math.Inf(1)
+	for _, l := range ml {
+		if lm, dist := l.measure(p); dist < minDist {
+			m, minDist = lm, dist
+		}
+	}
+	return m
+}
+
+// CalibrateMeasures returns a copy of ml with its measures recalculated in
+// proportion to the distance along ml, so that the measure is start at the
+// first point of ml and end at the last point. Gaps between the
+// LineStringMs in ml are not included in the distance. If ml has zero
+// length, all of the measures are set to start.
+func (ml MultiLineStringM) CalibrateMeasures(start, end float64) MultiLineStringM {
+	length := ml.Length()
+	o := make(MultiLineStringM, len(ml))
+	var pos float64
+	for i, l := range ml {
+		o[i] = make(LineStringM, len(l))
+		for j, p := range l {
+			if j > 0 {
+				pos += d(l[j-1].XY(), p.XY())
+			}
+			o[i][j] = PointM{X: p.X, Y: p.Y, M: start}
+			if length > 0 {
+				o[i][j].M = start + (end-start)*pos/length
+			}
+		}
+	}
+	// Avoid rounding errors in the final measure.
+	for i := len(o) - 1; i >= 0 && length > 0; i-- {
+		if len(o[i]) > 0 {
+			o[i][len(o[i])-1].M = end
+			break
+		}
+	}
+	return o
+}
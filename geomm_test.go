@@ -0,0 +1,94 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLocateMeasure(t *testing.T) {
+	l := LineStringM{{0, 0, 0}, {10, 0, 10}, {10, 10, 30}}
+	tests := []struct {
+		m  float64
+		p  Point
+		ok bool
+	}{
+		{m: 0, p: Point{0, 0}, ok: true},
+		{m: 5, p: Point{5, 0}, ok: true},
+		{m: 20, p: Point{10, 5}, ok: true},
+		{m: 30, p: Point{10, 10}, ok: true},
+		{m: 31, ok: false},
+		{m: -1, ok: false},
+	}
+	for i, test := range tests {
+		p, ok := l.LocateMeasure(test.m)
+		if ok != test.ok {
+			t.Errorf("%d: have ok %v, want %v", i, ok, test.ok)
+			continue
+		}
+		if ok && !p.Similar(test.p, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, p, test.p)
+		}
+		if ok {
+			if m := l.Measure(p); math.Abs(m-test.m) > 1e-12 {
+				t.Errorf("%d: have measure %g, want %g", i, m, test.m)
+			}
+		}
+	}
+
+	ml := MultiLineStringM{{{0, 0, 0}, {10, 0, 10}}, {{20, 0, 10}, {30, 0, 20}}}
+	if p, ok := ml.LocateMeasure(15); !ok || !p.Similar(Point{25, 0}, 1e-12) {
+		t.Errorf("have %v, %v, want {25 0}, true", p, ok)
+	}
+	if m := ml.Measure(Point{24, 3}); math.Abs(m-14) > 1e-12 {
+		t.Errorf("have measure %g, want 14", m)
+	}
+}
+
+func TestSubstringMeasure(t *testing.T) {
+	l := LineStringM{{0, 0, 0}, {10, 0, 10}, {10, 10, 30}}
+	tests := []struct {
+		start, end float64
+		want       LineStringM
+	}{
+		{start: 5, end: 20, want: LineStringM{{5, 0, 5}, {10, 0, 10}, {10, 5, 20}}},
+		{start: 20, end: 5, want: LineStringM{{10, 5, 20}, {10, 0, 10}, {5, 0, 5}}},
+		{start: 2, end: 8, want: LineStringM{{2, 0, 2}, {8, 0, 8}}},
+		{start: -10, end: 10, want: LineStringM{{0, 0, 0}, {10, 0, 10}}},
+		{start: 25, end: 100, want: LineStringM{{10, 7.5, 25}, {10, 10, 30}}},
+		{start: 40, end: 50, want: nil},
+	}
+	for i, test := range tests {
+		have := l.SubstringMeasure(test.start, test.end)
+		if test.want == nil {
+			if have != nil {
+				t.Errorf("%d: have %v, want nil", i, have)
+			}
+			continue
+		}
+		if !have.Similar(test.want, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+
+	ml := MultiLineStringM{{{0, 0, 0}, {10, 0, 10}}, {{20, 0, 10}, {30, 0, 20}}, {{0, 5, 30}, {0, 10, 35}}}
+	want := MultiLineStringM{{{5, 0, 5}, {10, 0, 10}}, {{20, 0, 10}, {25, 0, 15}}}
+	if have := ml.SubstringMeasure(5, 15); !have.Similar(want, 1e-12) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+}
+
+func TestCalibrateMeasures(t *testing.T) {
+	ml := MultiLineStringM{{{0, 0, 7}, {3, 4, 7}}, {{10, 0, 7}, {10, 15, 7}}}
+	want := MultiLineStringM{{{0, 0, 100}, {3, 4, 125}}, {{10, 0, 125}, {10, 15, 200}}}
+	if have := ml.CalibrateMeasures(100, 200); !have.Similar(want, 1e-12) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+	l := LineStringM{{0, 0, 0}, {0, 1, 0}, {0, 4, 0}}
+	wantL := LineStringM{{0, 0, 4}, {0, 1, 3}, {0, 4, 0}}
+	if have := l.CalibrateMeasures(4, 0); !have.Similar(wantL, 1e-12) {
+		t.Errorf("have %v, want %v", have, wantL)
+	}
+	if have := l.Force2D(); !have.Similar(LineString{{0, 0}, {0, 1}, {0, 4}}, 1e-12) {
+		t.Errorf("have %v", have)
+	}
+}
@@ -0,0 +1,606 @@
+package geom
+
+import (
+	"math"
+
+	"github.com/ctessum/geom/proj"
+)
+
+// GeomZ is an interface for geometry types whose points have Z (height)
+// coordinates as well as X and Y coordinates. The methods of the Geom
+// interface operate on the X and Y coordinates only, except for Similar,
+// which also compares Z coordinates, and Transform, which leaves Z
+// coordinates unchanged.
+type GeomZ interface {
+	Geom
+
+	// BoundsZ gives the extents of the geometry in all three dimensions.
+	BoundsZ() *BoundsZ
+
+	// TransformZ shifts the coordinates of the geometry, including the Z
+	// coordinates, according to the given transformer. This can be used
+	// to carry out three-dimensional datum shifts using
+	// proj.SR.NewTransform3D.
+	TransformZ(proj.Transformer3D) (GeomZ, error)
+
+	// Force2D returns a copy of the geometry with the Z coordinates
+	// removed.
+	Force2D() Geom
+}
+
+// PointZ is a holder for 3D coordinates X, Y and Z.
+type PointZ struct {
+	X, Y, Z float64
+}
+
+// XY returns the X and Y coordinates of p.
+func (p PointZ) XY() Point { return Point{X: p.X, Y: p.Y} }
+
+// MultiPointZ is a holder for multiple related points with Z coordinates.
+type MultiPointZ []PointZ
+
+// LineStringZ is a number of points with Z coordinates that make up a path
+// or line.
+type LineStringZ []PointZ
+
+// MultiLineStringZ is a holder for multiple related LineStringZs.
+type MultiLineStringZ []LineStringZ
+
+// PathZ is a series of connected points with Z coordinates.
+type PathZ []PointZ
+
+// PolygonZ is a series of closed rings with Z coordinates. As with
+// Polygon, the first ring is the outer ring and any further rings are
+// holes.
+type PolygonZ []PathZ
+
+// MultiPolygonZ is a holder for multiple related PolygonZs.
+type MultiPolygonZ []PolygonZ
+
+// BoundsZ holds the three-dimensional extent of a geometry.
+type BoundsZ struct {
+	Min, Max PointZ
+}
+
+// NewBoundsZ initializes a new, empty BoundsZ object.
+func NewBoundsZ() *BoundsZ {
+	return &BoundsZ{
+		PointZ{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)},
+		PointZ{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)},
+	}
+}
+
+// Extend increases the extent of b to include b2.
+func (b *BoundsZ) Extend(b2 *BoundsZ) {
+	if b2 == nil {
+		return
+	}
+	b.extendPoint(b2.Min)
+	b.extendPoint(b2.Max)
+}
+
+// Empty returns true if b does not contain any points.
+func (b *BoundsZ) Empty() bool {
+	return b.Max.X < b.Min.X || b.Max.Y < b.Min.Y || b.Max.Z < b.Min.Z
+}
+
+// Bounds returns the X and Y extents of b.
+func (b *BoundsZ) Bounds() *Bounds {
+	return &Bounds{Min: b.Min.XY(), Max: b.Max.XY()}
+}
+
+func (b *BoundsZ) extendPoint(p PointZ) *BoundsZ {
+	b.Min.X = math.Min(b.Min.X, p.X)
+	b.Min.Y = math.Min(b.Min.Y, p.Y)
+	b.Min.Z = math.Min(b.Min.Z, p.Z)
+	b.Max.X = math.Max(b.Max.X, p.X)
+	b.Max.Y = math.Max(b.Max.Y, p.Y)
+	b.Max.Z = math.Max(b.Max.Z, p.Z)
+	return b
+}
+
+func (b *BoundsZ) extendPoints(points []PointZ) *BoundsZ {
+	for _, p := range points {
+		b.extendPoint(p)
+	}
+	return b
+}
+
+func pointsXY(points []PointZ) []Point {
+	o := make([]Point, len(points))
+	for i, p := range points {
+		o[i] = p.XY()
+	}
+	return o
+}
+
+func pointZSimilar(p1, p2 PointZ, e float64) bool {
+	return similar(p1.X, p2.X, e) && similar(p1.Y, p2.Y, e) && similar(p1.Z, p2.Z, e)
+}
+
+func pointsZSimilar(p1s, p2s []PointZ, e float64) bool {
+	if len(p1s) != len(p2s) {
+		return false
+	}
+	for i := range p1s {
+		if !pointZSimilar(p1s[i], p2s[i], e) {
+			return false
+		}
+	}
+	return true
+}
+
+// transformPointsZ transforms the X and Y coordinates of points with t2 if
+// it is not nil, or all of the coordinates with t3 otherwise.
+func transformPointsZ(points []PointZ, t2 proj.Transformer, t3 proj.Transformer3D) ([]PointZ, error) {
+	o := make([]PointZ, len(points))
+	var err error
+	for i, p := range points {
+		if t2 != nil {
+			o[i].Z = p.Z
+			o[i].X, o[i].Y, err = t2(p.X, p.Y)
+		} else {
+			o[i].X, o[i].Y, o[i].Z, err = t3(p.X, p.Y, p.Z)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// pointsIterZ returns an iterator over the X and Y coordinates of the
+// points in pointss.
+func pointsIterZ(pointss ...[]PointZ) func() Point {
+	var i, j int
+	return func() Point {
+		for j >= len(pointss[i]) {
+			i++
+			j = 0
+		}
+		j++
+		return pointss[i][j-1].XY()
+	}
+}
+
+func length3D(points []PointZ) float64 {
+	length := 0.
+	for i := 0; i < len(points)-1; i++ {
+		p1, p2 := points[i], points[i+1]
+		length += math.Sqrt((p2.X-p1.X)*(p2.X-p1.X) +
+			(p2.Y-p1.Y)*(p2.Y-p1.Y) + (p2.Z-p1.Z)*(p2.Z-p1.Z))
+	}
+	return length
+}
+
+// Bounds gives the rectangular extents of the PointZ.
+func (p PointZ) Bounds() *Bounds { return NewBoundsPoint(p.XY()) }
+
+// BoundsZ gives the extents of the PointZ.
+func (p PointZ) BoundsZ() *BoundsZ { return &BoundsZ{Min: p, Max: p} }
+
+// Similar determines whether two geometries are similar within tolerance.
+func (p PointZ) Similar(g Geom, tolerance float64) bool {
+	p2, ok := g.(PointZ)
+	return ok && pointZSimilar(p, p2, tolerance)
+}
+
+// Transform shifts the X and Y coordinates of p according to t.
+func (p PointZ) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return p, nil
+	}
+	o, err := transformPointsZ([]PointZ{p}, t, nil)
+	if err != nil {
+		return nil, err
+	}
+	return o[0], nil
+}
+
+// TransformZ shifts the coordinates of p according to t.
+func (p PointZ) TransformZ(t proj.Transformer3D) (GeomZ, error) {
+	if t == nil {
+		return p, nil
+	}
+	o, err := transformPointsZ([]PointZ{p}, nil, t)
+	if err != nil {
+		return nil, err
+	}
+	return o[0], nil
+}
+
+// Force2D returns p without its Z coordinate.
+func (p PointZ) Force2D() Geom { return p.XY() }
+
+// Len returns the number of points in the receiver (always 1).
+func (p PointZ) Len() int { return 1 }
+
+// Points returns an iterator for the points in the receiver (there will
+// only be one point).
+func (p PointZ) Points() func() Point {
+	return func() Point { return p.XY() }
+}
+
+// Bounds gives the rectangular extents of the MultiPointZ.
+func (mp MultiPointZ) Bounds() *Bounds { return mp.BoundsZ().Bounds() }
+
+// BoundsZ gives the extents of the MultiPointZ.
+func (mp MultiPointZ) BoundsZ() *BoundsZ { return NewBoundsZ().extendPoints(mp) }
+
+// Similar determines whether two geometries are similar within tolerance.
+func (mp MultiPointZ) Similar(g Geom, tolerance float64) bool {
+	mp2, ok := g.(MultiPointZ)
+	return ok && pointsZSimilar(mp, mp2, tolerance)
+}
+
+// Transform shifts the X and Y coordinates of mp according to t.
+func (mp MultiPointZ) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return mp, nil
+	}
+	o, err := transformPointsZ(mp, t, nil)
+	if err != nil {
+		return nil, err
+	}
+	return MultiPointZ(o), nil
+}
+
+// TransformZ shifts the coordinates of mp according to t.
+func (mp MultiPointZ) TransformZ(t proj.Transformer3D) (GeomZ, error) {
+	if t == nil {
+		return mp, nil
+	}
+	o, err := transformPointsZ(mp, nil, t)
+	if err != nil {
+		return nil, err
+	}
+	return MultiPointZ(o), nil
+}
+
+// Force2D returns mp without its Z coordinates.
+func (mp MultiPointZ) Force2D() Geom { return MultiPoint(pointsXY(mp)) }
+
+// Len returns the number of points in the receiver.
+func (mp MultiPointZ) Len() int { return len(mp) }
+
+// Points returns an iterator for the points in the receiver.
+func (mp MultiPointZ) Points() func() Point { return pointsIterZ(mp) }
+
+// Bounds gives the rectangular extents of the LineStringZ.
+func (l LineStringZ) Bounds() *Bounds { return l.BoundsZ().Bounds() }
+
+// BoundsZ gives the extents of the LineStringZ.
+func (l LineStringZ) BoundsZ() *BoundsZ { return NewBoundsZ().extendPoints(l) }
+
+// Similar determines whether two geometries are similar within tolerance.
+func (l LineStringZ) Similar(g Geom, tolerance float64) bool {
+	l2, ok := g.(LineStringZ)
+	return ok && pointsZSimilar(l, l2, tolerance)
+}
+
+// Transform shifts the X and Y coordinates of l according to t.
+func (l LineStringZ) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return l, nil
+	}
+	o, err := transformPointsZ(l, t, nil)
+	if err != nil {
+		return nil, err
+	}
+	return LineStringZ(o), nil
+}
+
+// TransformZ shifts the coordinates of l according to t.
+func (l LineStringZ) TransformZ(t proj.Transformer3D) (GeomZ, error) {
+	if t == nil {
+		return l, nil
+	}
+	o, err := transformPointsZ(l, nil, t)
+	if err != nil {
+		return nil, err
+	}
+	return LineStringZ(o), nil
+}
+
+// Force2D returns l without its Z coordinates.
+func (l LineStringZ) Force2D() Geom { return LineString(pointsXY(l)) }
+
+// Length returns the length of l in the X-Y plane.
+func (l LineStringZ) Length() float64 { return LineString(pointsXY(l)).Length() }
+
+// Length3D returns the length of l in three dimensions.
+func (l LineStringZ) Length3D() float64 { return length3D(l) }
+
+// Len returns the number of points in the receiver.
+func (l LineStringZ) Len() int { return len(l) }
+
+// Points returns an iterator for the points in the receiver.
+func (l LineStringZ) Points() func() Point { return pointsIterZ(l) }
+
+// Bounds gives the rectangular extents of the MultiLineStringZ.
+func (ml MultiLineStringZ) Bounds() *Bounds { return ml.BoundsZ().Bounds() }
+
+// BoundsZ gives the extents of the MultiLineStringZ.
+func (ml MultiLineStringZ) BoundsZ() *BoundsZ {
+	b := NewBoundsZ()
+	for _, l := range ml {
+		b.extendPoints(l)
+	}
+	return b
+}
+
+// Similar determines whether two geometries are similar within tolerance.
+// Unlike MultiLineString.Similar, the LineStringZs must be in the same
+// order.
+func (ml MultiLineStringZ) Similar(g Geom, tolerance float64) bool {
+	ml2, ok := g.(MultiLineStringZ)
+	if !ok || len(ml) != len(ml2) {
+		return false
+	}
+	for i := range ml {
+		if !pointsZSimilar(ml[i], ml2[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform shifts the X and Y coordinates of ml according to t.
+func (ml MultiLineStringZ) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return ml, nil
+	}
+	o := make(MultiLineStringZ, len(ml))
+	for i, l := range ml {
+		l2, err := transformPointsZ(l, t, nil)
+		if err != nil {
+			return nil, err
+		}
+		o[i] = l2
+	}
+	return o, nil
+}
+
+// TransformZ shifts the coordinates of ml according to t.
+func (ml MultiLineStringZ) TransformZ(t proj.Transformer3D) (GeomZ, error) {
+	if t == nil {
+		return ml, nil
+	}
+	o := make(MultiLineStringZ, len(ml))
+	for i, l := range ml {
+		l2, err := transformPointsZ(l, nil, t)
+		if err != nil {
+			return nil, err
+		}
+		o[i] = l2
+	}
+	return o, nil
+}
+
+// Force2D returns ml without its Z coordinates.
+func (ml MultiLineStringZ) Force2D() Geom {
+	o := make(MultiLineString, len(ml))
+	for i, l := range ml {
+		o[i] = pointsXY(l)
+	}
+	return o
+}
+
+// Length returns the length of ml in the X-Y plane.
+func (ml MultiLineStringZ) Length() float64 { return ml.Force2D().(MultiLineString).Length() }
+
+// Length3D returns the length of ml in three dimensions.
+func (ml MultiLineStringZ) Length3D() float64 {
+	length := 0.
+	for _, l := range ml {
+		length += length3D(l)
+	}
+	return length
+}
+
+// Len returns the number of points in the receiver.
+func (ml MultiLineStringZ) Len() int {
+	var i int
+	for _, l := range ml {
+		i += len(l)
+	}
+	return i
+}
+
+// Points returns an iterator for the points in the receiver.
+func (ml MultiLineStringZ) Points() func() Point {
+	pointss := make([][]PointZ, len(ml))
+	for i, l := range ml {
+		pointss[i] = l
+	}
+	return pointsIterZ(pointss...)
+}
+
+// Bounds gives the rectangular extents of the PolygonZ.
+func (p PolygonZ) Bounds() *Bounds { return p.BoundsZ().Bounds() }
+
+// BoundsZ gives the extents of the PolygonZ.
+func (p PolygonZ) BoundsZ() *BoundsZ {
+	b := NewBoundsZ()
+	for _, r := range p {
+		b.extendPoints(r)
+	}
+	return b
+}
+
+// Similar determines whether two geometries are similar within tolerance.
+func (p PolygonZ) Similar(g Geom, tolerance float64) bool {
+	p2, ok := g.(PolygonZ)
+	return ok && pathsZSimilar(p, p2, tolerance)
+}
+
+func pathsZSimilar(p1, p2 []PathZ, tolerance float64) bool {
+	if len(p1) != len(p2) {
+		return false
+	}
+	for i := range p1 {
+		if !pointsZSimilar(p1[i], p2[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p PolygonZ) transform(t2 proj.Transformer, t3 proj.Transformer3D) (PolygonZ, error) {
+	o := make(PolygonZ, len(p))
+	for i, r := range p {
+		r2, err := transformPointsZ(r, t2, t3)
+		if err != nil {
+			return nil, err
+		}
+		o[i] = r2
+	}
+	return o, nil
+}
+
+// Transform shifts the X and Y coordinates of p according to t.
+func (p PolygonZ) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return p, nil
+	}
+	o, err := p.transform(t, nil)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// TransformZ shifts the coordinates of p according to t.
+func (p PolygonZ) TransformZ(t proj.Transformer3D) (GeomZ, error) {
+	if t == nil {
+		return p, nil
+	}
+	o, err := p.transform(nil, t)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Force2D returns p without its Z coordinates.
+func (p PolygonZ) Force2D() Geom { return p.force2D() }
+
+func (p PolygonZ) force2D() Polygon {
+	o := make(Polygon, len(p))
+	for i, r := range p {
+		o[i] = pointsXY(r)
+	}
+	return o
+}
+
+// Area returns the area of p in the X-Y plane.
+func (p PolygonZ) Area() float64 { return p.force2D().Area() }
+
+// Len returns the number of points in the receiver.
+func (p PolygonZ) Len() int {
+	var i int
+	for _, r := range p {
+		i += len(r)
+	}
+	return i
+}
+
+// Points returns an iterator for the points in the receiver.
+func (p PolygonZ) Points() func() Point {
+	pointss := make([][]PointZ, len(p))
+	for i, r := range p {
+		pointss[i] = r
+	}
+	return pointsIterZ(pointss...)
+}
+
+// Bounds gives the rectangular extents of the MultiPolygonZ.
+func (mp MultiPolygonZ) Bounds() *Bounds { return mp.BoundsZ().Bounds() }
+
+// BoundsZ gives the extents of the MultiPolygonZ.
+func (mp MultiPolygonZ) BoundsZ() *BoundsZ {
+	b := NewBoundsZ()
+	for _, p := range mp {
+		b.Extend(p.BoundsZ())
+	}
+	return b
+}
+
+// Similar determines whether two geometries are similar within tolerance.
+// The PolygonZs must be in the same order.
+func (mp MultiPolygonZ) Similar(g Geom, tolerance float64) bool {
+	mp2, ok := g.(MultiPolygonZ)
+	if !ok || len(mp) != len(mp2) {
+		return false
+	}
+	for i := range mp {
+		if !pathsZSimilar(mp[i], mp2[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform shifts the X and Y coordinates of mp according to t.
+func (mp MultiPolygonZ) Transform(t proj.Transformer) (Geom, error) {
+	if t == nil {
+		return mp, nil
+	}
+	o := make(MultiPolygonZ, len(mp))
+	for i, p := range mp {
+		p2, err := p.transform(t, nil)
+		if err != nil {
+			return nil, err
+		}
+		o[i] = p2
+	}
+	return o, nil
+}
+
+// TransformZ shifts the coordinates of mp according to t.
+func (mp MultiPolygonZ) TransformZ(t proj.Transformer3D) (GeomZ, error) {
+	if t == nil {
+		return mp, nil
+	}
+	o := make(MultiPolygonZ, len(mp))
+	for i, p := range mp {
+		p2, err := p.transform(nil, t)
+		if err != nil {
+			return nil, err
+		}
+		o[i] = p2
+	}
+	return o, nil
+}
+
+// Force2D returns mp without its Z coordinates.
+func (mp MultiPolygonZ) Force2D() Geom {
+	o := make(MultiPolygon, len(mp))
+	for i, p := range mp {
+		o[i] = p.force2D()
+	}
+	return o
+}
+
+// Area returns the area of mp in the X-Y plane.
+func (mp MultiPolygonZ) Area() float64 { return mp.Force2D().(MultiPolygon).Area() }
+
+// Len returns the number of points in the receiver.
+func (mp MultiPolygonZ) Len() int {
+	var i int
+	for _, p := range mp {
+		i += p.Len()
+	}
+	return i
+}
+
+// Points returns an iterator for the points in the receiver.
+func (mp MultiPolygonZ) Points() func() Point {
+	var pointss [][]PointZ
+	for _, p := range mp {
+		for _, r := range p {
+			pointss = append(pointss, r)
+		}
+	}
+	return pointsIterZ(pointss...)
+}
@@ -0,0 +1,120 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLength3D(t *testing.T) {
+	tests := []struct {
+		g          interface{ Length() float64 }
+		length2D   float64
+		length3D   float64
+		wantBounds BoundsZ
+	}{
+		{
+			g:          LineStringZ{{0, 0, 0}, {3, 4, 12}},
+			length2D:   5,
+			length3D:   13,
+			wantBounds: BoundsZ{Min: PointZ{0, 0, 0}, Max: PointZ{3, 4, 12}},
+		},
+		{
+			g:          MultiLineStringZ{{{0, 0, 0}, {3, 4, 12}}, {{0, 0, 5}, {0, 0, 1}}},
+			length2D:   5,
+			length3D:   17,
+			wantBounds: BoundsZ{Min: PointZ{0, 0, 0}, Max: PointZ{3, 4, 12}},
+		},
+	}
+	for i, test := range tests {
+		if have := test.g.Length(); have != test.length2D {
+			t.Errorf("%d: have 2D length %g, want %g", i, have, test.length2D)
+		}
+		if have := test.g.(interface{ Length3D() float64 }).Length3D(); have != test.length3D {
+			t.Errorf("%d: have 3D length %g, want %g", i, have, test.length3D)
+		}
+		if have := *test.g.(GeomZ).BoundsZ(); have != test.wantBounds {
+			t.Errorf("%d: have bounds %v, want %v", i, have, test.wantBounds)
+		}
+	}
+}
+
+func TestGeomZ(t *testing.T) {
+	shift := func(x, y float64) (float64, float64, error) { return x + 1, y + 2, nil }
+	shift3D := func(x, y, z float64) (float64, float64, float64, error) { return x + 1, y + 2, z + 3, nil }
+	tests := []struct {
+		g, transformed, transformed3D GeomZ
+		flat                          Geom
+	}{
+		{
+			g:             PointZ{1, 1, 1},
+			transformed:   PointZ{2, 3, 1},
+			transformed3D: PointZ{2, 3, 4},
+			flat:          Point{1, 1},
+		},
+		{
+			g:             MultiPointZ{{1, 1, 1}, {0, 0, 0}},
+			transformed:   MultiPointZ{{2, 3, 1}, {1, 2, 0}},
+			transformed3D: MultiPointZ{{2, 3, 4}, {1, 2, 3}},
+			flat:          MultiPoint{{1, 1}, {0, 0}},
+		},
+		{
+			g:             LineStringZ{{1, 1, 1}, {0, 0, 0}},
+			transformed:   LineStringZ{{2, 3, 1}, {1, 2, 0}},
+			transformed3D: LineStringZ{{2, 3, 4}, {1, 2, 3}},
+			flat:          LineString{{1, 1}, {0, 0}},
+		},
+		{
+			g:             MultiLineStringZ{{{1, 1, 1}, {0, 0, 0}}},
+			transformed:   MultiLineStringZ{{{2, 3, 1}, {1, 2, 0}}},
+			transformed3D: MultiLineStringZ{{{2, 3, 4}, {1, 2, 3}}},
+			flat:          MultiLineString{{{1, 1}, {0, 0}}},
+		},
+		{
+			g:             PolygonZ{{{0, 0, 0}, {1, 0, 1}, {1, 1, 2}, {0, 0, 0}}},
+			transformed:   PolygonZ{{{1, 2, 0}, {2, 2, 1}, {2, 3, 2}, {1, 2, 0}}},
+			transformed3D: PolygonZ{{{1, 2, 3}, {2, 2, 4}, {2, 3, 5}, {1, 2, 3}}},
+			flat:          Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		},
+		{
+			g:             MultiPolygonZ{{{{0, 0, 0}, {1, 0, 1}, {1, 1, 2}, {0, 0, 0}}}},
+			transformed:   MultiPolygonZ{{{{1, 2, 0}, {2, 2, 1}, {2, 3, 2}, {1, 2, 0}}}},
+			transformed3D: MultiPolygonZ{{{{1, 2, 3}, {2, 2, 4}, {2, 3, 5}, {1, 2, 3}}}},
+			flat:          MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		},
+	}
+	for i, test := range tests {
+		have, err := test.g.Transform(shift)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !have.Similar(test.transformed, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, have, test.transformed)
+		}
+		have, err = test.g.TransformZ(shift3D)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !have.Similar(test.transformed3D, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, have, test.transformed3D)
+		}
+		flat := test.g.Force2D()
+		if !flat.Similar(test.flat, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, flat, test.flat)
+		}
+		if have, want := *test.g.Bounds(), *test.flat.Bounds(); have != want {
+			t.Errorf("%d: have bounds %v, want %v", i, have, want)
+		}
+		if have, want := test.g.Len(), flat.Len(); have != want {
+			t.Fatalf("%d: have length %d, want %d", i, have, want)
+		}
+		next, nextFlat := test.g.Points(), flat.Points()
+		for j := 0; j < flat.Len(); j++ {
+			if have, want := next(), nextFlat(); have != want {
+				t.Errorf("%d, %d: have point %v, want %v", i, j, have, want)
+			}
+		}
+	}
+	if a := (PolygonZ{{{0, 0, 5}, {2, 0, 5}, {2, 2, 9}, {0, 2, 5}, {0, 0, 5}}}).Area(); math.Abs(a-4) > 1e-12 {
+		t.Errorf("have area %g, want 4", a)
+	}
+}
@@ -0,0 +1,209 @@
+// Package grid creates grids of square or hexagonal cells and reallocates
+// values from polygons to grid cells according to their overlapping areas.
+package grid
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/ctessum/geom"
+	"github.com/ctessum/geom/index/rtree"
+)
+
+// A Grid is a set of polygonal cells with a spatial index for finding
+// the cells at a location.
+type Grid struct {
+	Cells []geom.Polygon
+	index *rtree.Rtree
+}
+
+// cell is a grid cell stored in the spatial index.
+type cell struct {
+	geom.Polygon
+	i int
+}
+
+// New creates a grid from the given cells, which should not overlap
+// each other.
+func New(cells []geom.Polygon) *Grid {
+	g := &Grid{
+		Cells: cells,
+		index: rtree.NewTree(25, 50),
+	}
+	for i, c := range cells {
+		g.index.Insert(&cell{Polygon: c, i: i})
+	}
+	return g
+}
+
+// NewSquare creates a grid of rectangular cells with width dx and height
+// dy that covers b, starting at b.Min. Cell (row, column) is at index
+// row*nx + column, where nx is the number of columns and row 0 is at the
+// bottom of the grid.
+func NewSquare(b *geom.Bounds, dx, dy float64) *Grid {
+	if dx <= 0 || dy <= 0 {
+		panic(fmt.Errorf("grid: invalid cell size %g×%g", dx, dy))
+	}
+	nx := int(math.Max(1, math.Ceil((b.Max.X-b.Min.X)/dx)))
+	ny := int(math.Max(1, math.Ceil((b.Max.Y-b.Min.Y)/dy)))
+	cells := make([]geom.Polygon, 0, nx*ny)
+	for j := 0; j < ny; j++ {
+		y0, y1 := b.Min.Y+float64(j)*dy, b.Min.Y+float64(j+1)*dy
+		for i := 0; i < nx; i++ {
+			x0, x1 := b.Min.X+float64(i)*dx, b.Min.X+float64(i+1)*dx
+			cells = append(cells, geom.Polygon{{
+				{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}, {X: x0, Y: y0},
+			}})
+		}
+	}
+	return New(cells)
+}
+
+// NewHex creates a grid of flat-topped hexagonal cells with the given
+// circumradius (the distance from the center of a cell to its corners)
+// that covers b. The center of the first cell is at b.Min, and cells
+// that do not overlap b are omitted.
+func NewHex(b *geom.Bounds, radius float64) *Grid {
+	if radius <= 0 {
+		panic(fmt.Errorf("grid: invalid hexagon radius %g", radius))
+	}
+	h := math.Sqrt(3) * radius // The height of a cell.
+	var cells []geom.Polygon
+	for i := 0; b.Min.X+1.5*radius*float64(i)-radius < b.Max.X; i++ {
+		x := b.Min.X + 1.5*radius*float64(i)
+		offset := 0.
+		if i%2 == 1 {
+			offset = h / 2
+		}
+		for j := -1; b.Min.Y+h*float64(j)+offset-h/2 < b.Max.Y; j++ {
+			y := b.Min.Y + h*float64(j) + offset
+			if y+h/2 <= b.Min.Y {
+				continue
+			}
+			r := make(geom.Path, 7)
+			for k := 0; k < 6; k++ {
+				sin, cos := math.Sincos(float64(k) * math.Pi / 3)
+				r[k] = geom.Point{X: x + radius*cos, Y: y + radius*sin}
+			}
+			r[6] = r[0]
+			cells = append(cells, geom.Polygon{r})
+		}
+	}
+	return New(cells)
+}
+
+// Find returns the index of the cell that contains p, or -1 if p is not
+// within any of the cells. If p is on the edge between two cells, either
+// may be returned.
+func (g *Grid) Find(p geom.Point) int {
+	for _, c := range g.index.SearchIntersect(geom.NewBoundsPoint(p)) {
+		if p.Within(c.(*cell).Polygon) != geom.Outside {
+			return c.(*cell).i
+		}
+	}
+	return -1
+}
+
+// Overlapping returns the indices of the cells whose bounding boxes
+// overlap b, in increasing order.
+func (g *Grid) Overlapping(b *geom.Bounds) []int {
+	found := g.index.SearchIntersect(b)
+	o := make([]int, len(found))
+	for i, c := range found {
+		o[i] = c.(*cell).i
+	}
+	sort.Ints(o)
+	return o
+}
+
+// An Overlap holds the area shared by a source polygon and a grid cell.
+type Overlap struct {
+	// Source and Cell are the indices of the source polygon and grid cell.
+	Source, Cell int
+
+	// Area is the area of the intersection of the source and cell.
+	Area float64
+
+	// SourceFraction is the fraction of the area of the source polygon
+	// that is within the cell.
+	SourceFraction float64
+
+	// CellFraction is the fraction of the area of the cell that is
+	// within the source polygon.
+	CellFraction float64
+}
+
+// Overlaps calculates the overlaps between sources and the cells of g,
+// in order of source and then cell index. The calculations are carried
+// out in parallel.
+func (g *Grid) Overlaps(sources []geom.Polygonal) []Overlap {
+	perSource := make([][]Overlap, len(sources))
+	nprocs := runtime.GOMAXPROCS(-1)
+	var wg sync.WaitGroup
+	wg.Add(nprocs)
+	for p := 0; p < nprocs; p++ {
+		go func(p int) {
+			defer wg.Done()
+			for i := p; i < len(sources); i += nprocs {
+				perSource[i] = g.sourceOverlaps(i, sources[i])
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	var o []Overlap
+	for _, so := range perSource {
+		o = append(o, so...)
+	}
+	return o
+}
+
+// sourceOverlaps calculates the overlaps between src, which has index i,
+// and the cells of g.
+func (g *Grid) sourceOverlaps(i int, src geom.Polygonal) []Overlap {
+	srcArea := src.Area()
+	if srcArea == 0 {
+		return nil
+	}
+	var o []Overlap
+	for _, c := range g.Overlapping(src.Bounds()) {
+		isect := g.Cells[c].Intersection(src)
+		if isect == nil {
+			continue
+		}
+		a := isect.Area()
+		if a <= 0 {
+			continue
+		}
+		o = append(o, Overlap{
+			Source:         i,
+			Cell:           c,
+			Area:           a,
+			SourceFraction: a / srcArea,
+			CellFraction:   a / g.Cells[c].Area(),
+		})
+	}
+	return o
+}
+
+// Regrid reallocates values, which correspond to sources, to the cells
+// of g in proportion to the fraction of the area of each source that is
+// within each cell. The values are treated as totals (for example,
+// emissions in tonnes per year), so the total value is conserved where
+// the sources are entirely covered by the grid. For averages, such as
+// concentrations, use the CellFraction values of the returned overlaps
+// as weights instead.
+func (g *Grid) Regrid(sources []geom.Polygonal, values []float64) ([]float64, []Overlap) {
+	if len(sources) != len(values) {
+		panic(fmt.Errorf("grid: %d sources but %d values", len(sources), len(values)))
+	}
+	overlaps := g.Overlaps(sources)
+	o := make([]float64, len(g.Cells))
+	for _, ov := range overlaps {
+		o[ov.Cell] += values[ov.Source] * ov.SourceFraction
+	}
+	return o, overlaps
+}
@@ -0,0 +1,87 @@
+package grid
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/ctessum/geom"
+)
+
+func TestNewSquare(t *testing.T) {
+	g := NewSquare(&geom.Bounds{Min: geom.Point{X: 0, Y: 0}, Max: geom.Point{X: 3, Y: 1.5}}, 1, 1)
+	if len(g.Cells) != 6 {
+		t.Fatalf("have %d cells, want 6", len(g.Cells))
+	}
+	want := geom.Polygon{{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}, {X: 1, Y: 1}}}
+	if !reflect.DeepEqual(g.Cells[4], want) {
+		t.Errorf("cell 4: have %v, want %v", g.Cells[4], want)
+	}
+	tests := []struct {
+		p    geom.Point
+		want int
+	}{
+		{p: geom.Point{X: 0.5, Y: 0.5}, want: 0},
+		{p: geom.Point{X: 2.5, Y: 1.2}, want: 5},
+		{p: geom.Point{X: 3.5, Y: 0.5}, want: -1},
+	}
+	for i, test := range tests {
+		if have := g.Find(test.p); have != test.want {
+			t.Errorf("%d: have %d, want %d", i, have, test.want)
+		}
+	}
+}
+
+func TestNewHex(t *testing.T) {
+	b := &geom.Bounds{Min: geom.Point{X: 0, Y: 0}, Max: geom.Point{X: 10, Y: 7}}
+	g := NewHex(b, 1)
+	hexArea := 3 * math.Sqrt(3) / 2
+	var total float64
+	for i, c := range g.Cells {
+		if a := c.Area(); math.Abs(a-hexArea) > 1e-9 {
+			t.Errorf("cell %d: have area %g, want %g", i, a, hexArea)
+		}
+		if !c.Bounds().Overlaps(b) {
+			t.Errorf("cell %d does not overlap the bounds", i)
+		}
+		if isect := b.Intersection(c); isect != nil {
+			total += isect.Area()
+		}
+	}
+	// The cells should cover the bounds without overlapping.
+	if math.Abs(total-b.Area()) > 1e-6 {
+		t.Errorf("covered area: have %g, want %g", total, b.Area())
+	}
+	if i := g.Find(geom.Point{X: 0, Y: 0}); i < 0 || math.Abs(g.Cells[i][0][3].X+1) > 1e-12 || math.Abs(g.Cells[i][0][3].Y) > 1e-12 {
+		t.Errorf("the first cell should be centered on the origin")
+	}
+}
+
+func TestRegrid(t *testing.T) {
+	g := NewSquare(&geom.Bounds{Min: geom.Point{X: 0, Y: 0}, Max: geom.Point{X: 2, Y: 2}}, 1, 1)
+	sources := []geom.Polygonal{
+		geom.Polygon{{{X: 0.5, Y: 0.5}, {X: 1.5, Y: 0.5}, {X: 1.5, Y: 1.5}, {X: 0.5, Y: 1.5}}},
+		&geom.Bounds{Min: geom.Point{X: 0, Y: 0}, Max: geom.Point{X: 0.5, Y: 1}},
+		// This source is half outside of the grid.
+		geom.Polygon{{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 2}, {X: 1, Y: 2}}},
+	}
+	values, overlaps := g.Regrid(sources, []float64{4, 1, 2})
+	want := []float64{2, 1, 1, 2}
+	for i := range want {
+		if math.Abs(values[i]-want[i]) > 1e-9 {
+			t.Errorf("values: have %v, want %v", values, want)
+			break
+		}
+	}
+	if len(overlaps) != 6 {
+		t.Fatalf("have %d overlaps, want 6", len(overlaps))
+	}
+	o := overlaps[4]
+	wantO := Overlap{Source: 1, Cell: 0, Area: 0.5, SourceFraction: 1, CellFraction: 0.5}
+	if o.Source != wantO.Source || o.Cell != wantO.Cell ||
+		math.Abs(o.Area-wantO.Area) > 1e-9 ||
+		math.Abs(o.SourceFraction-wantO.SourceFraction) > 1e-9 ||
+		math.Abs(o.CellFraction-wantO.CellFraction) > 1e-9 {
+		t.Errorf("overlap: have %+v, want %+v", o, wantO)
+	}
+}
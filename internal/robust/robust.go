@@ -0,0 +1,244 @@
+// Package robust provides geometric predicates that give the correct answer
+// even when the inputs are nearly degenerate, following J. R. Shewchuk,
+// Adaptive precision floating-point arithmetic and fast robust geometric
+// predicates. Discrete Comput. Geom. 18(3), 305–363 (1997).
+//
+// Each predicate first evaluates its determinant with ordinary
+// floating-point arithmetic and checks the result against an error bound.
+// Only when the result is too close to zero for its sign to be trusted is
+// the determinant recalculated exactly using floating-point expansions.
+package robust
+
+const (
+	epsilon  = 1.1102230246251565e-16 // 2⁻⁵³
+	splitter = 134217729.             // 2²⁷ + 1
+
+	ccwErrBoundA = (3 + 16*epsilon) * epsilon
+	iccErrBoundA = (10 + 96*epsilon) * epsilon
+)
+
+// Orient2D returns a positive value if the points a, b, and c are in
+// counterclockwise order, a negative value if they are in clockwise order,
+// and zero if they are collinear. The magnitude of the result is
+// approximately twice the area of the triangle abc. The sign of the result
+// is always correct.
+func Orient2D(ax, ay, bx, by, cx, cy float64) float64 {
+	detLeft := (ax - cx) * (by - cy)
+	detRight := (ay - cy) * (bx - cx)
+	det := detLeft - detRight
+
+	var detSum float64
+	switch {
+	case detLeft > 0:
+		if detRight <= 0 {
+			return det
+		}
+		detSum = detLeft + detRight
+	case detLeft < 0:
+		if detRight >= 0 {
+			return det
+		}
+		detSum = -detLeft - detRight
+	default:
+		return det
+	}
+	if errBound := ccwErrBoundA * detSum; det >= errBound || -det >= errBound {
+		return det
+	}
+	return orient2DExact(ax, ay, bx, by, cx, cy)
+}
+
+// orient2DExact calculates the orientation determinant exactly by summing
+// its six terms, each of which is calculated exactly.
+func orient2DExact(ax, ay, bx, by, cx, cy float64) float64 {
+	var e []float64
+	for _, t := range [][3]float64{
+		{ax, by, 1}, {ay, bx, -1},
+		{bx, cy, 1}, {by, cx, -1},
+		{cx, ay, 1}, {cy, ax, -1},
+	} {
+		hi, lo := twoProduct(t[0]*t[2], t[1])
+		e = expansionSum(e, []float64{lo, hi})
+	}
+	return estimate(e)
+}
+
+// InCircle returns a positive value if the point d lies inside the circle
+// passing through a, b, and c, a negative value if it lies outside, and zero
+// if the four points are cocircular. The points a, b, and c must be in
+// counterclockwise order, or the sign of the result will be reversed.
+// The sign of the result is always correct.
+func InCircle(ax, ay, bx, by, cx, cy, dx, dy float64) float64 {
+	adx, ady := ax-dx, ay-dy
+	bdx, bdy := bx-dx, by-dy
+	cdx, cdy := cx-dx, cy-dy
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	aLift := adx*adx + ady*ady
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	bLift := bdx*bdx + bdy*bdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	cLift := cdx*cdx + cdy*cdy
+
+	det := aLift*(bdxcdy-cdxbdy) + bLift*(cdxady-adxcdy) + cLift*(adxbdy-bdxady)
+	permanent := (abs(bdxcdy)+abs(cdxbdy))*aLift +
+		(abs(cdxady)+abs(adxcdy))*bLift +
+		(abs(adxbdy)+abs(bdxady))*cLift
+	if errBound := iccErrBoundA * permanent; det > errBound || -det > errBound {
+		return det
+	}
+	return inCircleExact(ax, ay, bx, by, cx, cy, dx, dy)
+}
+
+// inCircleExact calculates the in-circle determinant exactly.
+func inCircleExact(ax, ay, bx, by, cx, cy, dx, dy float64) float64 {
+	diff := func(a, b float64) []float64 {
+		hi, lo := twoDiff(a, b)
+		return []float64{lo, hi}
+	}
+	adx, ady := diff(ax, dx), diff(ay, dy)
+	bdx, bdy := diff(bx, dx), diff(by, dy)
+	cdx, cdy := diff(cx, dx), diff(cy, dy)
+
+	lift := func(x, y []float64) []float64 {
+		return expansionSum(expansionProduct(x, x), expansionProduct(y, y))
+	}
+	cross := func(x1, y1, x2, y2 []float64) []float64 {
+		return expansionSum(expansionProduct(x1, y2), negate(expansionProduct(y1, x2)))
+	}
+	det := expansionProduct(lift(adx, ady), cross(bdx, bdy, cdx, cdy))
+	det = expansionSum(det, expansionProduct(lift(bdx, bdy), cross(cdx, cdy, adx, ady)))
+	det = expansionSum(det, expansionProduct(lift(cdx, cdy), cross(adx, ady, bdx, bdy)))
+	return estimate(det)
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// The functions below implement arithmetic on floating-point expansions:
+// sums of nonoverlapping float64 values ordered by increasing magnitude,
+// which together represent a number exactly.
+
+// twoSum returns a + b exactly as x + y, where x is the rounded sum.
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	return x, (a - av) + (b - bv)
+}
+
+// fastTwoSum is like twoSum, but requires that |a| >= |b|.
+func fastTwoSum(a, b float64) (x, y float64) {
+	x = a + b
+	return x, b - (x - a)
+}
+
+// twoDiff returns a - b exactly as x + y, where x is the rounded difference.
+func twoDiff(a, b float64) (x, y float64) {
+	x = a - b
+	bv := a - x
+	av := x + bv
+	return x, (a - av) + (bv - b)
+}
+
+// split splits a into two halves with at most 26 significant bits each.
+func split(a float64) (hi, lo float64) {
+	c := splitter * a
+	hi = c - (c - a)
+	return hi, a - hi
+}
+
+// twoProduct returns a * b exactly as x + y, where x is the rounded
+// product.
+func twoProduct(a, b float64) (x, y float64) {
+	x = a * b
+	ahi, alo := split(a)
+	bhi, blo := split(b)
+	err := x - ahi*bhi - alo*bhi - ahi*blo
+	return x, alo*blo - err
+}
+
+// growExpansion returns the expansion e + b, omitting zero components.
+func growExpansion(e []float64, b float64) []float64 {
+	h := make([]float64, 0, len(e)+1)
+	q := b
+	for _, ei := range e {
+		var hh float64
+		q, hh = twoSum(q, ei)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+// expansionSum returns the expansion e + f.
+func expansionSum(e, f []float64) []float64 {
+	h := e
+	for _, fi := range f {
+		h = growExpansion(h, fi)
+	}
+	return h
+}
+
+// scaleExpansion returns the expansion e * b, omitting zero components.
+func scaleExpansion(e []float64, b float64) []float64 {
+	if len(e) == 0 {
+		return []float64{0}
+	}
+	h := make([]float64, 0, 2*len(e))
+	q, hh := twoProduct(e[0], b)
+	if hh != 0 {
+		h = append(h, hh)
+	}
+	for _, ei := range e[1:] {
+		p1, p0 := twoProduct(ei, b)
+		sum, hh := twoSum(q, p0)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+		q, hh = fastTwoSum(p1, sum)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+// expansionProduct returns the expansion e * f.
+func expansionProduct(e, f []float64) []float64 {
+	var h []float64
+	for _, fi := range f {
+		h = expansionSum(h, scaleExpansion(e, fi))
+	}
+	return h
+}
+
+// negate returns the expansion -e.
+func negate(e []float64) []float64 {
+	o := make([]float64, len(e))
+	for i, ei := range e {
+		o[i] = -ei
+	}
+	return o
+}
+
+// estimate returns the floating-point value closest to the expansion e,
+// which always has the same sign as e.
+func estimate(e []float64) float64 {
+	var s float64
+	for _, ei := range e {
+		s += ei
+	}
+	return s
+}
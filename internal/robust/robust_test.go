@@ -0,0 +1,108 @@
+package robust
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func orient2DRat(ax, ay, bx, by, cx, cy float64) int {
+	r := func(x float64) *big.Rat { return new(big.Rat).SetFloat64(x) }
+	acx := new(big.Rat).Sub(r(ax), r(cx))
+	bcy := new(big.Rat).Sub(r(by), r(cy))
+	acy := new(big.Rat).Sub(r(ay), r(cy))
+	bcx := new(big.Rat).Sub(r(bx), r(cx))
+	left := new(big.Rat).Mul(acx, bcy)
+	right := new(big.Rat).Mul(acy, bcx)
+	return left.Cmp(right)
+}
+
+func inCircleRat(ax, ay, bx, by, cx, cy, dx, dy float64) int {
+	r := func(x float64) *big.Rat { return new(big.Rat).SetFloat64(x) }
+	sub := func(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) }
+	add := func(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) }
+	mul := func(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) }
+	adx, ady := sub(r(ax), r(dx)), sub(r(ay), r(dy))
+	bdx, bdy := sub(r(bx), r(dx)), sub(r(by), r(dy))
+	cdx, cdy := sub(r(cx), r(dx)), sub(r(cy), r(dy))
+	aLift := add(mul(adx, adx), mul(ady, ady))
+	bLift := add(mul(bdx, bdx), mul(bdy, bdy))
+	cLift := add(mul(cdx, cdx), mul(cdy, cdy))
+	det := mul(aLift, sub(mul(bdx, cdy), mul(cdx, bdy)))
+	det = add(det, mul(bLift, sub(mul(cdx, ady), mul(adx, cdy))))
+	det = add(det, mul(cLift, sub(mul(adx, bdy), mul(bdx, ady))))
+	return det.Sign()
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func TestOrient2D(t *testing.T) {
+	tests := []struct {
+		ax, ay, bx, by, cx, cy float64
+		want                   int
+	}{
+		{0, 0, 1, 0, 0, 1, 1},
+		{0, 0, 0, 1, 1, 0, -1},
+		{0, 0, 1, 1, 2, 2, 0},
+	}
+	for i, test := range tests {
+		have := sign(Orient2D(test.ax, test.ay, test.bx, test.by, test.cx, test.cy))
+		if have != test.want {
+			t.Errorf("%d: have %d, want %d", i, have, test.want)
+		}
+	}
+
+	// Points very close to the line through (12, 12) and (24, 24), where
+	// plain floating-point arithmetic gives inconsistent answers.
+	ulp := math.Pow(2, -53)
+	for i := 0; i < 256; i++ {
+		for j := 0; j < 256; j++ {
+			ax, ay := 0.5+float64(i)*ulp, 0.5+float64(j)*ulp
+			have := sign(Orient2D(ax, ay, 12, 12, 24, 24))
+			want := orient2DRat(ax, ay, 12, 12, 24, 24)
+			if have != want {
+				t.Fatalf("(%d, %d): have %d, want %d", i, j, have, want)
+			}
+		}
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	if have := sign(InCircle(0, 0, 1, 0, 0, 1, 0.5, 0.5)); have != 1 {
+		t.Errorf("inside: have %d, want 1", have)
+	}
+	if have := sign(InCircle(0, 0, 1, 0, 0, 1, 2, 2)); have != -1 {
+		t.Errorf("outside: have %d, want -1", have)
+	}
+	if have := sign(InCircle(0, 0, 1, 0, 0, 1, 1, 1)); have != 0 {
+		t.Errorf("cocircular: have %d, want 0", have)
+	}
+
+	// Points that are nearly on the unit circle.
+	rnd := rand.New(rand.NewSource(1))
+	onCircle := func() (float64, float64) {
+		a := rnd.Float64() * 2 * math.Pi
+		return math.Cos(a), math.Sin(a)
+	}
+	for i := 0; i < 1000; i++ {
+		ax, ay := onCircle()
+		bx, by := onCircle()
+		cx, cy := onCircle()
+		dx, dy := onCircle()
+		have := sign(InCircle(ax, ay, bx, by, cx, cy, dx, dy))
+		want := inCircleRat(ax, ay, bx, by, cx, cy, dx, dy)
+		if have != want {
+			t.Fatalf("%d: have %d, want %d", i, have, want)
+		}
+	}
+}
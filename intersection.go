@@ -1,6 +1,10 @@
 package geom
 
-import "math"
+import (
+	"math"
+
+	"github.com/ctessum/geom/internal/robust"
+)
 
 var nanPoint Point
 
@@ -38,41 +42,37 @@ func findIntersection(seg0, seg1 segment) (int, Point, Point) {
 	d0 := Point{seg0.end.X - p0.X, seg0.end.Y - p0.Y}
 	p1 := seg1.start
 	d1 := Point{seg1.end.X - p1.X, seg1.end.Y - p1.Y}
-	sqrEpsilon := 0. // was 1e-3 earlier
 	E := Point{p1.X - p0.X, p1.Y - p0.Y}
-	kross := d0.X*d1.Y - d0.Y*d1.X
-	sqrKross := kross * kross
-	sqrLen0 := lengthToOrigin(d0)
-	sqrLen1 := lengthToOrigin(d1)
-
-	if sqrKross > sqrEpsilon*sqrLen0*sqrLen1 {
-		// lines of the segments are not parallel
-		s := (E.X*d1.Y - E.Y*d1.X) / kross
-		if s < 0 || s > 1 {
-			return 0, Point{}, Point{}
-		}
-		t := (E.X*d0.Y - E.Y*d0.X) / kross
-		if t < 0 || t > 1 {
+	sqrLen0 := dot(d0, d0)
+
+	o1 := orient(seg0.start, seg0.end, seg1.start)
+	o2 := orient(seg0.start, seg0.end, seg1.end)
+	o3 := orient(seg1.start, seg1.end, seg0.start)
+	o4 := orient(seg1.start, seg1.end, seg0.end)
+	if o1 != 0 || o2 != 0 || o3 != 0 || o4 != 0 {
+		// lines of the segments are not the same
+		if sameSide(o1, o2) || sameSide(o3, o4) {
 			return 0, nanPoint, nanPoint
 		}
-		// intersection of lines is a point an each segment [MC: ?]
+		// Where an endpoint is on the other segment, return it exactly.
+		switch {
+		case o1 == 0:
+			return 1, seg1.start, nanPoint
+		case o2 == 0:
+			return 1, seg1.end, nanPoint
+		case o3 == 0:
+			return 1, seg0.start, nanPoint
+		case o4 == 0:
+			return 1, seg0.end, nanPoint
+		}
+		// o3 and o4 are proportional to the distances of the ends of seg0
+		// from the line of seg1.
+		s := o3 / (o3 - o4)
 		pi0.X = p0.X + s*d0.X
 		pi0.Y = p0.Y + s*d0.Y
-
-		// [MC: commented fragment removed]
-
 		return 1, pi0, nanPoint
 	}
 
-	// lines of the segments are parallel
-	sqrLenE := lengthToOrigin(E)
-	kross = E.X*d0.Y - E.Y*d0.X
-	sqrKross = kross * kross
-	if sqrKross > sqrEpsilon*sqrLen0*sqrLenE {
-		// lines of the segment are different
-		return 0, nanPoint, nanPoint
-	}
-
 	// Lines of the segment are the same. Need to test for overlap of segments.
 	// s0 = Dot (D0, E) * sqrLen0
 	s0 := (d0.X*E.X + d0.Y*E.Y) / sqrLen0
@@ -129,9 +129,17 @@ func findIntersection2(u0, u1, v0, v1 float64, w *[]float64) int {
 	return 2
 }
 
-// Length returns distance from p to point (0, 0).
-func lengthToOrigin(p Point) float64 {
-	return math.Sqrt(p.X*p.X + p.Y*p.Y)
+// orient returns a positive value if a, b, and c are in counterclockwise
+// order, a negative value if they are in clockwise order, and zero if they
+// are collinear. The sign of the result is exact.
+func orient(a, b, c Point) float64 {
+	return robust.Orient2D(a.X, a.Y, b.X, b.Y, c.X, c.Y)
+}
+
+// sameSide returns whether orientations a and b are both positive or both
+// negative.
+func sameSide(a, b float64) bool {
+	return a > 0 && b > 0 || a < 0 && b < 0
 }
 
 // Used to represent an edge of a polygon.
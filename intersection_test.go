@@ -0,0 +1,53 @@
+package geom
+
+import "testing"
+
+func TestFindIntersection(t *testing.T) {
+	tests := []struct {
+		seg0, seg1 segment
+		n          int
+		p0, p1     Point
+	}{
+		{
+			seg0: segment{Point{0, 0}, Point{2, 2}},
+			seg1: segment{Point{0, 2}, Point{2, 0}},
+			n:    1, p0: Point{1, 1},
+		},
+		{
+			seg0: segment{Point{0, 0}, Point{2, 0}},
+			seg1: segment{Point{0, 1}, Point{2, 1}},
+			n:    0,
+		},
+		{
+			// The overlap of collinear segments is found by projecting
+			// seg1 onto seg0, which requires dividing by the squared
+			// length of seg0 rather than its length.
+			seg0: segment{Point{0, 0}, Point{4, 0}},
+			seg1: segment{Point{1, 0}, Point{2, 0}},
+			n:    2, p0: Point{1, 0}, p1: Point{2, 0},
+		},
+		{
+			seg0: segment{Point{0, 0}, Point{0, 3}},
+			seg1: segment{Point{0, 3}, Point{0, 5}},
+			n:    1, p0: Point{0, 3},
+		},
+		{
+			seg0: segment{Point{0, 0}, Point{3, 3}},
+			seg1: segment{Point{4, 4}, Point{5, 5}},
+			n:    0,
+		},
+	}
+	for i, test := range tests {
+		n, p0, p1 := findIntersection(test.seg0, test.seg1)
+		if n != test.n {
+			t.Errorf("%d: have %d intersections, want %d", i, n, test.n)
+			continue
+		}
+		if n > 0 && !p0.Equals(test.p0) {
+			t.Errorf("%d: first point: have %v, want %v", i, p0, test.p0)
+		}
+		if n > 1 && !p1.Equals(test.p1) {
+			t.Errorf("%d: second point: have %v, want %v", i, p1, test.p1)
+		}
+	}
+}
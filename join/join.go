@@ -0,0 +1,302 @@
+// Package join matches the features in one set of geometries with the
+// features in another set according to their spatial relationships.
+package join
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/ctessum/geom"
+	"github.com/ctessum/geom/index/rtree"
+)
+
+// Predicate specifies the spatial relationship that a left and right
+// feature must have to match.
+type Predicate int
+
+const (
+	// Intersects matches features that share at least one point,
+	// including where one is inside of a polygon in the other.
+	Intersects Predicate = iota
+
+	// Within matches left features that are within or on the edge of a
+	// polygonal right feature.
+	Within
+
+	// Contains matches polygonal left features that contain right
+	// features within them or on their edges.
+	Contains
+
+	// Nearest matches each left feature with the Options.K right features
+	// that are closest to it.
+	Nearest
+
+	// WithinDistance matches features that are no farther than
+	// Options.Distance from each other.
+	WithinDistance
+)
+
+// Options specifies how features are matched. The zero value matches
+// each left feature with all of the right features that satisfy the
+// predicate, without calculating overlap areas.
+type Options struct {
+	// K is the number of right features matched with each left feature by
+	// Nearest. Values less than one are treated as one.
+	K int
+
+	// Distance is the maximum distance between matching features for
+	// WithinDistance.
+	Distance float64
+
+	// OneToOne specifies that each left feature should only be matched
+	// with its best match: the nearest right feature for Nearest and
+	// WithinDistance; the right feature with the greatest overlap area if
+	// AreaWeights is true; and otherwise the first matching right
+	// feature.
+	OneToOne bool
+
+	// AreaWeights specifies that the area of overlap should be calculated
+	// for matching features that are both polygonal.
+	AreaWeights bool
+}
+
+// A Match is a pair of matching features.
+type Match struct {
+	// Left and Right are the indices of the left and right features.
+	Left, Right int
+
+	// Distance is the shortest distance between the features, which is
+	// zero if they intersect.
+	Distance float64
+
+	// Area is the area of the intersection of the features, if they are
+	// both polygonal and Options.AreaWeights is true.
+	Area float64
+
+	// LeftFraction and RightFraction are the fractions of the areas of
+	// the left and right features that are within the intersection. They
+	// can be used as weights to transfer values between the feature sets.
+	LeftFraction, RightFraction float64
+}
+
+// feature is a right feature stored in the spatial index.
+type feature struct {
+	geom.Geom
+	i int
+}
+
+// SpatialJoin returns the pairs of features from left and right that
+// satisfy predicate, in order of left feature. The matches for each left
+// feature are in order of distance for Nearest and WithinDistance, and in
+// order of right feature otherwise. Nil and empty features never match.
+// The right features are indexed in an R-tree, and the left features are
+// processed in parallel. If opts is nil, the zero Options are used.
+func SpatialJoin(left, right []geom.Geom, predicate Predicate, opts *Options) []Match {
+	if opts == nil {
+		opts = &Options{}
+	}
+	switch predicate {
+	case Intersects, Within, Contains, Nearest:
+	case WithinDistance:
+		if !(opts.Distance >= 0) {
+			panic(fmt.Errorf("join: invalid distance %g", opts.Distance))
+		}
+	default:
+		panic(fmt.Errorf("join: invalid predicate %d", predicate))
+	}
+	j := &joiner{right: right, predicate: predicate, opts: opts, index: rtree.NewTree(25, 50)}
+	for i, r := range right {
+		if r != nil && !r.Bounds().Empty() {
+			j.index.Insert(&feature{Geom: r, i: i})
+		}
+	}
+
+	perLeft := make([][]Match, len(left))
+	nprocs := runtime.GOMAXPROCS(-1)
+	var wg sync.WaitGroup
+	wg.Add(nprocs)
+	for p := 0; p < nprocs; p++ {
+		go func(p int) {
+			defer wg.Done()
+			for i := p; i < len(left); i += nprocs {
+				if left[i] != nil && !left[i].Bounds().Empty() {
+					perLeft[i] = j.matches(i, left[i])
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	var o []Match
+	for _, m := range perLeft {
+		o = append(o, m...)
+	}
+	return o
+}
+
+// joiner holds the state of a spatial join.
+type joiner struct {
+	right     []geom.Geom
+	predicate Predicate
+	opts      *Options
+	index     *rtree.Rtree
+}
+
+// matches returns the matches for left feature l, which has index i.
+func (j *joiner) matches(i int, l geom.Geom) []Match {
+	var o []Match
+	b := l.Bounds()
+	switch j.predicate {
+	case Intersects:
+		for _, r := range j.candidates(b, 0) {
+			if geom.IsWithinDistance(l, r.Geom, 0) {
+				o = append(o, Match{Left: i, Right: r.i})
+			}
+		}
+	case Within:
+		for _, r := range j.candidates(b, 0) {
+			if within(l, r.Geom) {
+				o = append(o, Match{Left: i, Right: r.i})
+			}
+		}
+	case Contains:
+		for _, r := range j.candidates(b, 0) {
+			if within(r.Geom, l) {
+				o = append(o, Match{Left: i, Right: r.i})
+			}
+		}
+	case WithinDistance:
+		for _, r := range j.candidates(b, j.opts.Distance) {
+			if geom.IsWithinDistance(l, r.Geom, j.opts.Distance) {
+				o = append(o, Match{Left: i, Right: r.i, Distance: geom.Distance(l, r.Geom)})
+			}
+		}
+	case Nearest:
+		o = j.nearest(i, l)
+	}
+
+	if j.opts.AreaWeights {
+		for k := range o {
+			o[k].setArea(l, j.right[o[k].Right])
+		}
+	}
+	sort.SliceStable(o, func(a, b int) bool {
+		switch {
+		case j.predicate == Nearest || j.predicate == WithinDistance:
+			if o[a].Distance != o[b].Distance {
+				return o[a].Distance < o[b].Distance
+			}
+		case j.opts.OneToOne && j.opts.AreaWeights:
+			if o[a].Area != o[b].Area {
+				return o[a].Area > o[b].Area
+			}
+		}
+		return o[a].Right < o[b].Right
+	})
+	if j.opts.OneToOne && len(o) > 1 {
+		o = o[:1]
+	}
+	return o
+}
+
+// candidates returns the right features whose bounding boxes are no
+// farther than dist from b.
+func (j *joiner) candidates(b *geom.Bounds, dist float64) []*feature {
+	search := &geom.Bounds{
+		Min: geom.Point{X: b.Min.X - dist, Y: b.Min.Y - dist},
+		Max: geom.Point{X: b.Max.X + dist, Y: b.Max.Y + dist},
+	}
+	found := j.index.SearchIntersect(search)
+	o := make([]*feature, len(found))
+	for i, f := range found {
+		o[i] = f.(*feature)
+	}
+	return o
+}
+
+// nearest returns the matches between left feature l, which has index i,
+// and the opts.K right features that are closest to it.
+func (j *joiner) nearest(i int, l geom.Geom) []Match {
+	k := j.opts.K
+	if k < 1 {
+		k = 1
+	}
+	// The features that are nearest to the center of l give an upper
+	// bound on the distance to the nearest features to l as a whole.
+	b := l.Bounds()
+	center := geom.Point{X: (b.Min.X + b.Max.X) / 2, Y: (b.Min.Y + b.Max.Y) / 2}
+	var maxDist float64
+	for _, f := range j.index.NearestNeighbors(k, center) {
+		if f == nil {
+			continue
+		}
+		maxDist = math.Max(maxDist, geom.Distance(l, f.(*feature).Geom))
+	}
+	var o []Match
+	for _, r := range j.candidates(b, maxDist) {
+		o = append(o, Match{Left: i, Right: r.i, Distance: geom.Distance(l, r.Geom)})
+	}
+	sort.Slice(o, func(a, b int) bool {
+		if o[a].Distance != o[b].Distance {
+			return o[a].Distance < o[b].Distance
+		}
+		return o[a].Right < o[b].Right
+	})
+	if len(o) > k {
+		o = o[:k]
+	}
+	return o
+}
+
+// setArea sets the overlap area of m, whose features are l and r, if they
+// are both polygonal.
+func (m *Match) setArea(l, r geom.Geom) {
+	lp, ok1 := l.(geom.Polygonal)
+	rp, ok2 := r.(geom.Polygonal)
+	if !ok1 || !ok2 {
+		return
+	}
+	isect := lp.Intersection(rp)
+	if isect == nil {
+		return
+	}
+	m.Area = isect.Area()
+	if a := lp.Area(); a > 0 {
+		m.LeftFraction = m.Area / a
+	}
+	if a := rp.Area(); a > 0 {
+		m.RightFraction = m.Area / a
+	}
+}
+
+// within returns whether g is within or on the edge of pg, which must be
+// polygonal.
+func within(g, pg geom.Geom) bool {
+	p, ok := pg.(geom.Polygonal)
+	if !ok {
+		return false
+	}
+	switch g := g.(type) {
+	case geom.Withiner:
+		return g.Within(p) != geom.Outside
+	case geom.Polygonal:
+		for _, gp := range g.Polygons() {
+			if gp.Within(p) == geom.Outside {
+				return false
+			}
+		}
+		return true
+	case geom.GeometryCollection:
+		for _, gg := range g {
+			if !within(gg, p) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,110 @@
+package join
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/ctessum/geom"
+)
+
+func square(x, y, size float64) geom.Polygon {
+	return geom.Polygon{{{X: x, Y: y}, {X: x + size, Y: y}, {X: x + size, Y: y + size}, {X: x, Y: y + size}, {X: x, Y: y}}}
+}
+
+// pairs returns the left and right indices of matches.
+func pairs(matches []Match) [][2]int {
+	o := make([][2]int, len(matches))
+	for i, m := range matches {
+		o[i] = [2]int{m.Left, m.Right}
+	}
+	return o
+}
+
+func TestSpatialJoin(t *testing.T) {
+	left := []geom.Geom{
+		geom.Point{X: 0.5, Y: 0.5},
+		geom.LineString{{X: 0.5, Y: 1.5}, {X: 1.5, Y: 1.5}},
+		square(0, 0, 2),
+		nil,
+		geom.Point{X: 10, Y: 10},
+	}
+	right := []geom.Geom{
+		square(0, 0, 1),
+		square(1, 0, 1),
+		square(0, 1, 1),
+		square(1, 1, 1),
+		geom.Point{X: 1.5, Y: 0.5},
+		geom.Point{X: 12, Y: 10},
+	}
+	tests := []struct {
+		predicate Predicate
+		opts      *Options
+		want      [][2]int
+	}{
+		{
+			predicate: Intersects,
+			want: [][2]int{
+				{0, 0},
+				{1, 2}, {1, 3},
+				{2, 0}, {2, 1}, {2, 2}, {2, 3}, {2, 4},
+			},
+		},
+		{
+			predicate: Intersects,
+			opts:      &Options{OneToOne: true},
+			want:      [][2]int{{0, 0}, {1, 2}, {2, 0}},
+		},
+		{
+			predicate: Within,
+			want:      [][2]int{{0, 0}},
+		},
+		{
+			predicate: Contains,
+			want:      [][2]int{{2, 0}, {2, 1}, {2, 2}, {2, 3}, {2, 4}},
+		},
+		{
+			predicate: Nearest,
+			opts:      &Options{K: 2},
+			want:      [][2]int{{0, 0}, {0, 1}, {1, 2}, {1, 3}, {2, 0}, {2, 1}, {4, 5}, {4, 3}},
+		},
+		{
+			predicate: WithinDistance,
+			opts:      &Options{Distance: 2},
+			want:      [][2]int{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 0}, {1, 1}, {1, 4}, {2, 0}, {2, 1}, {2, 2}, {2, 3}, {2, 4}, {4, 5}},
+		},
+	}
+	for i, test := range tests {
+		have := pairs(SpatialJoin(left, right, test.predicate, test.opts))
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestSpatialJoin_areaWeights(t *testing.T) {
+	left := []geom.Geom{square(0.5, 0, 1), square(5, 5, 1)}
+	right := []geom.Geom{square(0, 0, 1), square(1, 0, 2), geom.Point{X: 1, Y: 0.5}}
+	matches := SpatialJoin(left, right, Intersects, &Options{AreaWeights: true})
+	want := []Match{
+		{Left: 0, Right: 0, Area: 0.5, LeftFraction: 0.5, RightFraction: 0.5},
+		{Left: 0, Right: 1, Area: 0.5, LeftFraction: 0.5, RightFraction: 0.125},
+		{Left: 0, Right: 2},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("have %v, want %v", matches, want)
+	}
+	for i, m := range matches {
+		w := want[i]
+		if m.Left != w.Left || m.Right != w.Right || math.Abs(m.Area-w.Area) > 1e-12 ||
+			math.Abs(m.LeftFraction-w.LeftFraction) > 1e-12 || math.Abs(m.RightFraction-w.RightFraction) > 1e-12 {
+			t.Errorf("%d: have %+v, want %+v", i, m, w)
+		}
+	}
+
+	left[0] = square(0.25, 0, 1)
+	matches = SpatialJoin(left, right, Intersects, &Options{AreaWeights: true, OneToOne: true})
+	if len(matches) != 1 || matches[0].Right != 0 {
+		t.Errorf("one to one: have %+v, want match with 0", matches)
+	}
+}
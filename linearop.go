@@ -0,0 +1,171 @@
+package geom
+
+// LinearIntersection returns the points where a crosses or touches b,
+// and the parts of a that overlap b.
+func LinearIntersection(a, b Linear) (MultiPoint, MultiLineString) {
+	return linesIntersection(linearLines(a), linearLines(b))
+}
+
+// LinearDifference returns the parts of l that fall outside of p. Parts of
+// l that lie on the edge of p are not included in the result.
+func LinearDifference(l Linear, p Polygonal) MultiLineString {
+	return linesDifference(linearLines(l), p)
+}
+
+// LinearUnion returns the combination of a and b. Overlapping parts of
+// the two lines are only included once, and the lines are split
+// where they cross each other.
+func LinearUnion(a, b Linear) MultiLineString {
+	return append(append(MultiLineString{}, linearLines(a)...), linearLines(b)...).dissolve()
+}
+
+// Intersection returns the points where l crosses or touches l2, and the
+// parts of l that overlap l2.
+func (l LineString) Intersection(l2 Linear) (MultiPoint, MultiLineString) {
+	return LinearIntersection(l, l2)
+}
+
+// Intersection returns the points where ml crosses or touches l2, and the
+// parts of ml that overlap l2.
+func (ml MultiLineString) Intersection(l2 Linear) (MultiPoint, MultiLineString) {
+	return LinearIntersection(ml, l2)
+}
+
+// Difference returns the parts of l that fall outside of p. Parts of l
+// that lie on the edge of p are not included in the result.
+func (l LineString) Difference(p Polygonal) Linear {
+	return LinearDifference(l, p)
+}
+
+// Difference returns the parts of ml that fall outside of p. Parts of ml
+// that lie on the edge of p are not included in the result.
+func (ml MultiLineString) Difference(p Polygonal) Linear {
+	return LinearDifference(ml, p)
+}
+
+// Union returns the combination of l and l2. Overlapping parts of
+// the two lines are only included once, and the lines are split
+// where they cross each other.
+func (l LineString) Union(l2 Linear) Linear {
+	return LinearUnion(l, l2)
+}
+
+// Union returns the combination of ml and l2. Overlapping parts of
+// the two lines are only included once, and the lines are split
+// where they cross each other.
+func (ml MultiLineString) Union(l2 Linear) Linear {
+	return LinearUnion(ml, l2)
+}
+
+// dissolve removes the overlapping parts of the lines in ml and joins
+// the remaining pieces into lines that only meet at their endpoints.
+func (ml MultiLineString) dissolve() MultiLineString {
+	return Node(ml).LineMerge()
+}
+
+func linesIntersection(a, b MultiLineString) (MultiPoint, MultiLineString) {
+	pieces := splitLines(append(append(MultiLineString{}, a...), b...))
+	var bPieces []LineString
+	for _, lp := range pieces[len(a):] {
+		bPieces = append(bPieces, lp...)
+	}
+	var overlaps MultiLineString
+	for _, lp := range pieces[:len(a)] {
+		for _, piece := range lp {
+			for _, bPiece := range bPieces {
+				if sameLine(piece, bPiece) {
+					overlaps = append(overlaps, piece)
+					break
+				}
+			}
+		}
+	}
+	overlaps = Node(overlaps).LineMerge()
+
+	var points MultiPoint
+	seen := make(map[Point]struct{})
+	for _, la := range a {
+		for i := 0; i < len(la)-1; i++ {
+			segA := segment{la[i], la[i+1]}
+			for _, lb := range b {
+				for j := 0; j < len(lb)-1; j++ {
+					segB := segment{lb[j], lb[j+1]}
+					n, p, _ := findIntersection(segA, segB)
+					if n != 1 {
+						continue
+					}
+					p = snapToEndpoints(p, segA, segB)
+					if _, ok := seen[p]; ok {
+						continue
+					}
+					seen[p] = struct{}{}
+					if overlaps.Len() == 0 || overlaps.Distance(p) > 0 {
+						points = append(points, p)
+					}
+				}
+			}
+		}
+	}
+	return points, overlaps
+}
+
+func linesDifference(ml MultiLineString, p Polygonal) MultiLineString {
+	lines := append(MultiLineString{}, ml...)
+	for _, poly := range p.Polygons() {
+		for _, r := range poly {
+			if len(r) == 0 {
+				continue
+			}
+			ring := append(LineString{}, r...)
+			if !ring[0].Equals(ring[len(ring)-1]) {
+				ring = append(ring, ring[0])
+			}
+			lines = append(lines, ring)
+		}
+	}
+	var o MultiLineString
+	for _, lp := range splitLines(lines)[:len(ml)] {
+		joinNext := false
+		for _, piece := range lp {
+			if pointInPolygonal(piece.Interpolate(piece.Length()/2), p) != Outside {
+				joinNext = false
+				continue
+			}
+			if joinNext {
+				o[len(o)-1] = append(o[len(o)-1], piece[1:]...)
+			} else {
+				o = append(o, piece)
+			}
+			joinNext = true
+		}
+	}
+	return o
+}
+
+// multiLineStringer is implemented by Linear types that can return their
+// lines as a MultiLineString, such as FlatMultiLineString.
+type multiLineStringer interface {
+	MultiLineString() MultiLineString
+}
+
+// linearLines returns the lines that make up l. Linear types other than
+// LineString and MultiLineString are converted with their
+// MultiLineString method if they have one, and are otherwise treated as a
+// single line through the points returned by their Points iterator.
+func linearLines(l Linear) MultiLineString {
+	switch l := l.(type) {
+	case LineString:
+		return MultiLineString{l}
+	case MultiLineString:
+		return l
+	case multiLineStringer:
+		return l.MultiLineString()
+	default:
+		next := l.Points()
+		o := make(LineString, l.Len())
+		for i := range o {
+			o[i] = next()
+		}
+		return MultiLineString{o}
+	}
+}
@@ -0,0 +1,72 @@
+package geom
+
+import "testing"
+
+func TestLinearIntersection(t *testing.T) {
+	a := LineString{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}}
+	b := MultiLineString{
+		{{X: 1, Y: -1}, {X: 1, Y: 1}},
+		{{X: 3, Y: 0}, {X: 5, Y: 0}, {X: 4, Y: 1}, {X: 4, Y: 2}, {X: 6, Y: 2}},
+	}
+	points, overlaps := a.Intersection(b)
+	wantPoints := MultiPoint{{X: 1, Y: 0}}
+	wantOverlaps := MultiLineString{
+		{{X: 3, Y: 0}, {X: 4, Y: 0}},
+		{{X: 4, Y: 1}, {X: 4, Y: 2}},
+	}
+	if !points.Similar(wantPoints, 1.e-9) {
+		t.Errorf("points: have %v, want %v", points, wantPoints)
+	}
+	if !overlaps.Similar(wantOverlaps, 1.e-9) || len(overlaps) != len(wantOverlaps) {
+		t.Errorf("overlaps: have %v, want %v", overlaps, wantOverlaps)
+	}
+}
+
+func TestLinearDifference(t *testing.T) {
+	l := LineString{{X: -1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}}
+	p := Polygon{{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}, {X: 0, Y: 0}}}
+	want := MultiLineString{
+		{{X: -1, Y: 1}, {X: 0, Y: 1}},
+		{{X: 2, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}},
+	}
+	if got := l.Difference(p); !got.Similar(want, 1.e-9) {
+		t.Errorf("have %v, want %v", got, want)
+	}
+}
+
+func TestLinearUnion(t *testing.T) {
+	a := LineString{{X: 0, Y: 0}, {X: 2, Y: 0}}
+	b := LineString{{X: 1, Y: 0}, {X: 3, Y: 0}}
+	want := MultiLineString{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}}
+	got := a.Union(b)
+	if !got.Similar(want, 1.e-9) {
+		t.Errorf("have %v, want %v", got, want)
+	}
+	if got.Length() != 3 {
+		t.Errorf("length: have %g, want 3", got.Length())
+	}
+}
+
+// wrappedLine is a Linear type that is not known to the linear operations.
+type wrappedLine struct {
+	LineString
+}
+
+func TestLinearOtherType(t *testing.T) {
+	a := wrappedLine{LineString{{X: 0, Y: 0}, {X: 2, Y: 0}}}
+	b := LineString{{X: 1, Y: -1}, {X: 1, Y: 1}}
+	points, overlaps := LinearIntersection(a, b)
+	if want := (MultiPoint{{X: 1, Y: 0}}); !points.Similar(want, 1.e-9) || len(overlaps) != 0 {
+		t.Errorf("intersection: have %v, %v, want %v", points, overlaps, want)
+	}
+	p := Polygon{{{X: 1, Y: -1}, {X: 3, Y: -1}, {X: 3, Y: 1}, {X: 1, Y: 1}}}
+	if have, want := LinearDifference(a, p), (MultiLineString{{{X: 0, Y: 0}, {X: 1, Y: 0}}}); !have.Similar(want, 1.e-9) {
+		t.Errorf("difference: have %v, want %v", have, want)
+	}
+	if have := LinearUnion(a, b); have.Length() != 4 || len(have) != 4 {
+		t.Errorf("union: have %v", have)
+	}
+	if have := b.Union(a); have.Length() != 4 {
+		t.Errorf("union method: have %v", have)
+	}
+}
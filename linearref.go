@@ -0,0 +1,240 @@
+package geom
+
+import "math"
+
+// Interpolate returns the point that lies the given distance along l,
+// measured from its first point. Distances less than zero or greater than
+// the length of l are clamped to the start or end of the line, respectively.
+func (l LineString) Interpolate(distance float64) Point {
+	if len(l) == 0 {
+		return nanPoint
+	}
+	if distance <= 0 {
+		return l[0]
+	}
+	for i := 0; i < len(l)-1; i++ {
+		segLen := d(l[i], l[i+1])
+		if distance <= segLen {
+			return interpolateSegment(l[i], l[i+1], distance/segLen)
+		}
+		distance -= segLen
+	}
+	return l[len(l)-1]
+}
+
+// Locate returns the distance along l, measured from its first point, of
+// the point on l that is closest to p (i.e., the projection of p onto l).
+func (l LineString) Locate(p Point) float64 {
+	minDist := math.Inf(1)
+	var pos, length float64
+	for i := 0; i < len(l)-1; i++ {
+		segLen := d(l[i], l[i+1])
+		f := projectSegment(p, l[i], l[i+1])
+		dist := d(p, interpolateSegment(l[i], l[i+1], f))
+		if dist < minDist {
+			minDist = dist
+			pos = length + f*segLen
+		}
+		length += segLen
+	}
+	return pos
+}
+
+// Substring returns the part of l that lies between the distances start
+// and end along l, measured from its first point. Distances are clamped to
+// the extent of l, and if end is less than start the returned line runs in
+// the opposite direction from l.
+func (l LineString) Substring(start, end float64) LineString {
+	if len(l) == 0 {
+		return LineString{}
+	}
+	if end < start {
+		s := l.Substring(end, start)
+		for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+			s[i], s[j] = s[j], s[i]
+		}
+		return s
+	}
+	length := l.Length()
+	start = math.Max(0, math.Min(start, length))
+	end = math.Max(0, math.Min(end, length))
+
+	o := LineString{l.Interpolate(start)}
+	var pos float64
+	for i := 0; i < len(l)-1; i++ {
+		pos += d(l[i], l[i+1])
+		if pos > start && pos < end {
+			o = append(o, l[i+1])
+		}
+	}
+	return append(o, l.Interpolate(end))
+}
+
+// Interpolate returns the point that lies the given distance along ml,
+// where the distance is measured along each of the linestrings in ml in
+// order. Distances less than zero or greater than the length of ml are
+// clamped to the start or end of ml, respectively.
+func (ml MultiLineString) Interpolate(distance float64) Point {
+	for i, l := range ml {
+		length := l.Length()
+		if distance <= length || i == len(ml)-1 {
+			return l.Interpolate(distance)
+		}
+		distance -= length
+	}
+	return nanPoint
+}
+
+// Locate returns the distance along ml, measured along each of the
+// linestrings in ml in order, of the point on ml that is closest to p.
+func (ml MultiLineString) Locate(p Point) float64 {
+	minDist := math.Inf(1)
+	var pos, length float64
+	for _, l := range ml {
+		if dist := l.Distance(p); dist < minDist {
+			minDist = dist
+			pos = length + l.Locate(p)
+		}
+		length += l.Length()
+	}
+	return pos
+}
+
+// Interpolate returns the point that lies the given distance along l,
+// measured along each of its lines in order. Distances less than zero or
+// greater than the length of l are clamped to the start or end of l,
+// respectively.
+func Interpolate(l Linear, distance float64) Point {
+	return linearLines(l).Interpolate(distance)
+}
+
+// Locate returns the distance along l, measured along each of its lines in
+// order, of the point on l that is closest to p.
+func Locate(l Linear, p Point) float64 {
+	return linearLines(l).Locate(p)
+}
+
+// Substring returns the parts of ml that lie between the distances start
+// and end along ml, where distances are measured along each of the
+// linestrings in ml in order. Linestrings that do not overlap the
+// requested range are omitted from the result.
+func (ml MultiLineString) Substring(start, end float64) MultiLineString {
+	if end < start {
+		start, end = end, start
+	}
+	var o MultiLineString
+	var pos float64
+	for _, l := range ml {
+		length := l.Length()
+		if pos+length >= start && pos <= end && len(l) > 0 {
+			o = append(o, l.Substring(start-pos, end-pos))
+		}
+		pos += length
+	}
+	return o
+}
+
+// LineMerge joins the linestrings in ml that share endpoints into
+// maximal linestrings. Two linestrings are only joined where exactly two
+// linestring endpoints meet; linestrings may be reversed to allow them to
+// be joined. Linestrings with fewer than two points are dropped.
+func (ml MultiLineString) LineMerge() MultiLineString {
+	type end struct {
+		line  int
+		start bool
+	}
+	nodes := make(map[Point][]end)
+	for i, l := range ml {
+		if len(l) < 2 {
+			continue
+		}
+		nodes[l[0]] = append(nodes[l[0]], end{line: i, start: true})
+		nodes[l[len(l)-1]] = append(nodes[l[len(l)-1]], end{line: i, start: false})
+	}
+
+	used := make([]bool, len(ml))
+	// walk follows unused linestrings starting from endpoint e until it
+	// reaches a node where other than two linestrings meet.
+	walk := func(e end) LineString {
+		var o LineString
+		for {
+			used[e.line] = true
+			l := ml[e.line]
+			if e.start {
+				for _, p := range l {
+					o = appendNew(o, p)
+				}
+			} else {
+				for i := len(l) - 1; i >= 0; i-- {
+					o = appendNew(o, l[i])
+				}
+			}
+			next := nodes[o[len(o)-1]]
+			if len(next) != 2 {
+				return o
+			}
+			found := false
+			for _, n := range next {
+				if !used[n.line] {
+					e = n
+					found = true
+					break
+				}
+			}
+			if !found {
+				return o
+			}
+		}
+	}
+
+	var o MultiLineString
+	// First start from the nodes where other than two lines meet...
+	for _, l := range ml {
+		if len(l) < 2 {
+			continue
+		}
+		for _, p := range []Point{l[0], l[len(l)-1]} {
+			if len(nodes[p]) == 2 {
+				continue
+			}
+			for _, e := range nodes[p] {
+				if !used[e.line] {
+					o = append(o, walk(e))
+				}
+			}
+		}
+	}
+	// ...then the remaining lines must be parts of closed rings.
+	for i, l := range ml {
+		if len(l) >= 2 && !used[i] {
+			o = append(o, walk(end{line: i, start: true}))
+		}
+	}
+	return o
+}
+
+// appendNew appends p to l unless it is equal to the last point in l.
+func appendNew(l LineString, p Point) LineString {
+	if len(l) > 0 && l[len(l)-1].Equals(p) {
+		return l
+	}
+	return append(l, p)
+}
+
+// interpolateSegment returns the point that is fraction f of the way from
+// a to b.
+func interpolateSegment(a, b Point, f float64) Point {
+	return Point{X: a.X + f*(b.X-a.X), Y: a.Y + f*(b.Y-a.Y)}
+}
+
+// projectSegment returns the fraction of the distance from segStart to
+// segEnd of the point on the segment that is closest to p.
+func projectSegment(p, segStart, segEnd Point) float64 {
+	v := pointSubtract(segEnd, segStart)
+	c2 := dot(v, v)
+	if c2 == 0 {
+		return 0
+	}
+	f := dot(pointSubtract(p, segStart), v) / c2
+	return math.Max(0, math.Min(1, f))
+}
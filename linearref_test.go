@@ -0,0 +1,122 @@
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	l := LineString{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}}
+	tests := []struct {
+		distance float64
+		want     Point
+	}{
+		{distance: -1, want: Point{X: 0, Y: 0}},
+		{distance: 1, want: Point{X: 1, Y: 0}},
+		{distance: 3, want: Point{X: 2, Y: 1}},
+		{distance: 5, want: Point{X: 2, Y: 2}},
+	}
+	for i, test := range tests {
+		if got := l.Interpolate(test.distance); got != test.want {
+			t.Errorf("%d: have %v, want %v", i, got, test.want)
+		}
+	}
+	ml := MultiLineString{l, {{X: 5, Y: 5}, {X: 5, Y: 7}}}
+	if got, want := ml.Interpolate(5), (Point{X: 5, Y: 6}); got != want {
+		t.Errorf("MultiLineString: have %v, want %v", got, want)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	l := LineString{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}}
+	tests := []struct {
+		p    Point
+		want float64
+	}{
+		{p: Point{X: 1, Y: 1}, want: 1},
+		{p: Point{X: 3, Y: 1.5}, want: 3.5},
+		{p: Point{X: -1, Y: -1}, want: 0},
+		{p: Point{X: 2, Y: 5}, want: 4},
+	}
+	for i, test := range tests {
+		if got := l.Locate(test.p); got != test.want {
+			t.Errorf("%d: have %g, want %g", i, got, test.want)
+		}
+	}
+	ml := MultiLineString{l, {{X: 5, Y: 5}, {X: 5, Y: 7}}}
+	if got, want := ml.Locate(Point{X: 6, Y: 6}), 5.; got != want {
+		t.Errorf("MultiLineString: have %g, want %g", got, want)
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	l := LineString{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}}
+	tests := []struct {
+		start, end float64
+		want       LineString
+	}{
+		{start: 1, end: 3, want: LineString{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}}},
+		{start: 3, end: 1, want: LineString{{X: 2, Y: 1}, {X: 2, Y: 0}, {X: 1, Y: 0}}},
+		{start: -1, end: 1, want: LineString{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+		{start: 0.5, end: 1.5, want: LineString{{X: 0.5, Y: 0}, {X: 1.5, Y: 0}}},
+	}
+	for i, test := range tests {
+		if got := l.Substring(test.start, test.end); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d: have %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestLineMerge(t *testing.T) {
+	tests := []struct {
+		input, want MultiLineString
+	}{
+		{
+			input: MultiLineString{
+				{{X: 1, Y: 0}, {X: 2, Y: 0}},
+				{{X: 0, Y: 0}, {X: 1, Y: 0}},
+				{{X: 3, Y: 0}, {X: 2, Y: 0}},
+			},
+			want: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}},
+			},
+		},
+		{
+			// Three lines meeting at a single node are not merged.
+			input: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}},
+				{{X: 1, Y: 0}, {X: 2, Y: 0}},
+				{{X: 1, Y: 0}, {X: 1, Y: 1}},
+			},
+			want: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}},
+				{{X: 1, Y: 0}, {X: 2, Y: 0}},
+				{{X: 1, Y: 0}, {X: 1, Y: 1}},
+			},
+		},
+		{
+			input: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}},
+				{{X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}},
+			},
+			want: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}},
+			},
+		},
+	}
+	for i, test := range tests {
+		if got := test.input.LineMerge(); !got.Similar(test.want, 1.e-9) {
+			t.Errorf("%d: have %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestInterpolateLocateLinear(t *testing.T) {
+	l := wrappedLine{LineString{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}}}
+	if got, want := Interpolate(l, 3), (Point{X: 2, Y: 1}); got != want {
+		t.Errorf("Interpolate: have %v, want %v", got, want)
+	}
+	if got, want := Locate(l, Point{X: 3, Y: 1.5}), 3.5; got != want {
+		t.Errorf("Locate: have %g, want %g", got, want)
+	}
+}
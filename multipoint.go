@@ -1,5 +1,7 @@
 package geom
 
+import "sort"
+
 // MultiPoint is a holder for multiple related points.
 type MultiPoint []Point
 
@@ -34,3 +36,39 @@ func (mp MultiPoint) Points() func() Point {
 		return mp[i-1]
 	}
 }
+
+// ConvexHull returns the smallest convex polygon that contains all of the
+// points in mp, as a closed counterclockwise ring. It returns nil if mp
+// has fewer than three points that are not all on the same line.
+func (mp MultiPoint) ConvexHull() Polygon {
+	if len(mp) < 3 {
+		return nil
+	}
+	pts := append(MultiPoint{}, mp...)
+	sort.Slice(pts, func(i, j int) bool {
+		return pts[i].X < pts[j].X || (pts[i].X == pts[j].X && pts[i].Y < pts[j].Y)
+	})
+	// Build the lower and upper hulls with Andrew's monotone chain
+	// algorithm.
+	hull := make(Path, 0, len(pts)+1)
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range pts {
+			for len(hull) >= start+2 && orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	if len(hull) < 3 {
+		return nil
+	}
+	return Polygon{append(hull, hull[0])}
+}
+
+// Distance calculates the shortest distance from p to the MultiPoint.
+func (mp MultiPoint) Distance(p Point) float64 { return Distance(mp, p) }
@@ -0,0 +1,34 @@
+package geom
+
+import "testing"
+
+func TestMultiPointConvexHull(t *testing.T) {
+	tests := []struct {
+		mp   MultiPoint
+		want Polygon
+	}{
+		{
+			mp:   MultiPoint{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 0, Y: 2}, {X: 1, Y: 0}},
+			want: Polygon{{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}, {X: 0, Y: 0}}},
+		},
+		{
+			mp: MultiPoint{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}},
+		},
+		{
+			mp: MultiPoint{{X: 1, Y: 1}, {X: 1, Y: 1}},
+		},
+		{},
+	}
+	for i, test := range tests {
+		have := test.mp.ConvexHull()
+		if test.want == nil {
+			if have != nil {
+				t.Errorf("%d: have %v, want nil", i, have)
+			}
+			continue
+		}
+		if !have.Similar(test.want, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
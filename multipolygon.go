@@ -122,3 +122,7 @@ func (mp MultiPolygon) Points() func() Point {
 		return mp[k][j][i-1]
 	}
 }
+
+// Distance calculates the shortest distance from p to the MultiPolygon. It
+// is zero if p is inside of the MultiPolygon.
+func (mp MultiPolygon) Distance(p Point) float64 { return Distance(mp, p) }
@@ -0,0 +1,157 @@
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+// Node splits the linestrings in ml at every point where they cross or touch
+// themselves or each other, so that the returned linestrings only meet at
+// their endpoints. Overlapping (collinear) parts of the input are returned
+// only once, so the result can be used as the edges of a planar graph,
+// for example as input to Polygonize.
+func Node(ml MultiLineString) MultiLineString {
+	var o MultiLineString
+	seen := make(map[[2]Point][]LineString)
+	for _, pieces := range splitLines(ml) {
+		for _, piece := range pieces {
+			key := [2]Point{piece[0], piece[len(piece)-1]}
+			rkey := [2]Point{key[1], key[0]}
+			duplicate := false
+			for _, other := range append(seen[key], seen[rkey]...) {
+				if sameLine(piece, other) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				seen[key] = append(seen[key], piece)
+				o = append(o, piece)
+			}
+		}
+	}
+	return o
+}
+
+// splitLines splits the linestrings in ml at every point where they cross or
+// touch themselves or each other, and returns the pieces that each
+// linestring in ml has been split into.
+func splitLines(ml MultiLineString) [][]LineString {
+	type segRef struct {
+		line, index int
+		b           *Bounds
+	}
+	// splits[i][j] holds the points where segment j of line i
+	// needs to be split.
+	splits := make([][][]Point, len(ml))
+	nodes := make(map[Point]struct{})
+	var segs []segRef
+	for i, l := range ml {
+		if len(l) < 2 {
+			continue
+		}
+		splits[i] = make([][]Point, len(l)-1)
+		nodes[l[0]] = struct{}{}
+		nodes[l[len(l)-1]] = struct{}{}
+		for j := 0; j < len(l)-1; j++ {
+			b := NewBoundsPoint(l[j])
+			b.extendPoint(l[j+1])
+			segs = append(segs, segRef{line: i, index: j, b: b})
+		}
+	}
+
+	// Sweep along the x axis so that only segments with overlapping x
+	// ranges are compared.
+	sort.Slice(segs, func(i, j int) bool { return segs[i].b.Min.X < segs[j].b.Min.X })
+	for a, sa := range segs {
+		segA := segment{ml[sa.line][sa.index], ml[sa.line][sa.index+1]}
+		for _, sb := range segs[a+1:] {
+			if sb.b.Min.X > sa.b.Max.X {
+				break
+			}
+			if !sa.b.Overlaps(sb.b) {
+				continue
+			}
+			segB := segment{ml[sb.line][sb.index], ml[sb.line][sb.index+1]}
+			n, p0, p1 := findIntersection(segA, segB)
+			for _, p := range []Point{p0, p1}[:n] {
+				p = snapToEndpoints(p, segA, segB)
+				if sa.line == sb.line && adjacentSegments(sa.index, sb.index, ml[sa.line]) &&
+					(p.Equals(segA.start) || p.Equals(segA.end)) &&
+					(p.Equals(segB.start) || p.Equals(segB.end)) {
+					// Adjacent segments always share a vertex.
+					continue
+				}
+				nodes[p] = struct{}{}
+				splits[sa.line][sa.index] = append(splits[sa.line][sa.index], p)
+				splits[sb.line][sb.index] = append(splits[sb.line][sb.index], p)
+			}
+		}
+	}
+
+	o := make([][]LineString, len(ml))
+	for i, l := range ml {
+		if len(l) < 2 {
+			continue
+		}
+		piece := LineString{l[0]}
+		for j := 0; j < len(l)-1; j++ {
+			sp := splits[i][j]
+			sort.Slice(sp, func(a, b int) bool {
+				return d(l[j], sp[a]) < d(l[j], sp[b])
+			})
+			for _, p := range append(sp, l[j+1]) {
+				if piece[len(piece)-1].Equals(p) {
+					continue
+				}
+				piece = append(piece, p)
+				if _, ok := nodes[p]; ok {
+					o[i] = append(o[i], piece)
+					piece = LineString{p}
+				}
+			}
+		}
+		if len(piece) > 1 {
+			o[i] = append(o[i], piece)
+		}
+	}
+	return o
+}
+
+// adjacentSegments returns whether segments i and j of l share a vertex.
+func adjacentSegments(i, j int, l LineString) bool {
+	if i > j {
+		i, j = j, i
+	}
+	return j == i+1 || (i == 0 && j == len(l)-2 && l[0].Equals(l[len(l)-1]))
+}
+
+// snapToEndpoints returns the segment endpoint that p is equal to within
+// floating point error, or p if there is no such endpoint.
+func snapToEndpoints(p Point, seg0, seg1 segment) Point {
+	for _, e := range []Point{seg0.start, seg0.end, seg1.start, seg1.end} {
+		tol := 1.e-12 * math.Max(1, math.Max(math.Abs(e.X), math.Abs(e.Y)))
+		if pointSimilar(p, e, tol) {
+			return e
+		}
+	}
+	return p
+}
+
+// sameLine returns whether a and b contain the same points, in either
+// direction.
+func sameLine(a, b LineString) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	forward, backward := true, true
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			forward = false
+		}
+		if !a[i].Equals(b[len(b)-1-i]) {
+			backward = false
+		}
+	}
+	return forward || backward
+}
@@ -0,0 +1,83 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNode(t *testing.T) {
+	tests := []struct {
+		input, want MultiLineString
+	}{
+		{
+			input: MultiLineString{
+				{{X: 0, Y: 1}, {X: 2, Y: 1}},
+				{{X: 1, Y: 0}, {X: 1, Y: 2}},
+			},
+			want: MultiLineString{
+				{{X: 0, Y: 1}, {X: 1, Y: 1}},
+				{{X: 1, Y: 1}, {X: 2, Y: 1}},
+				{{X: 1, Y: 0}, {X: 1, Y: 1}},
+				{{X: 1, Y: 1}, {X: 1, Y: 2}},
+			},
+		},
+		{
+			// Overlapping lines.
+			input: MultiLineString{
+				{{X: 0, Y: 0}, {X: 2, Y: 0}},
+				{{X: 3, Y: 0}, {X: 1, Y: 0}},
+			},
+			want: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}},
+				{{X: 1, Y: 0}, {X: 2, Y: 0}},
+				{{X: 3, Y: 0}, {X: 2, Y: 0}},
+			},
+		},
+		{
+			// Self intersection.
+			input: MultiLineString{
+				{{X: 0, Y: 0}, {X: 2, Y: 2}, {X: 2, Y: 0}, {X: 0, Y: 2}},
+			},
+			want: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 1}},
+				{{X: 1, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 0}, {X: 1, Y: 1}},
+				{{X: 1, Y: 1}, {X: 0, Y: 2}},
+			},
+		},
+		{
+			// Closed ring with no intersections.
+			input: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}},
+			},
+			want: MultiLineString{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}},
+			},
+		},
+	}
+	for i, test := range tests {
+		if got := Node(test.input); !got.Similar(test.want, 1.e-9) || len(got) != len(test.want) {
+			t.Errorf("%d: have %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestNodeGrid(t *testing.T) {
+	const n = 100
+	var ml MultiLineString
+	for i := 0; i < n; i++ {
+		ml = append(ml,
+			LineString{{X: 0, Y: float64(i)}, {X: n - 1, Y: float64(i)}},
+			LineString{{X: float64(i), Y: 0}, {X: float64(i), Y: n - 1}},
+		)
+	}
+	o := Node(ml)
+	if want := 2 * n * (n - 1); len(o) != want {
+		t.Errorf("have %d pieces, want %d", len(o), want)
+	}
+	for i, l := range o {
+		if len(l) != 2 || math.Abs(d(l[0], l[1])-1) > 1.e-9 {
+			t.Errorf("%d: have %v, want a line of length 1", i, l)
+			break
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+// JoinStyle specifies how the offset segments on the outside of a corner
+// are joined together.
+type JoinStyle int
+
+const (
+	// RoundJoin joins segments with a circular arc around the corner.
+	RoundJoin JoinStyle = iota
+	// MiterJoin extends the segments until they meet.
+	MiterJoin
+	// BevelJoin joins the ends of the segments with a straight line.
+	BevelJoin
+)
+
+// OffsetOptions specifies how an offset curve is constructed.
+type OffsetOptions struct {
+	// Join is the style of the joins at the outside of corners.
+	Join JoinStyle
+
+	// QuadrantSegments is the number of segments used to approximate a
+	// quarter circle in round joins. If it is zero, 8 is used.
+	QuadrantSegments int
+
+	// MiterLimit is the greatest allowed ratio of the distance between a
+	// corner and its miter point to the offset distance. Miter joins that
+	// would exceed the limit are beveled instead. If it is zero, 5 is
+	// used.
+	MiterLimit float64
+}
+
+// OffsetCurve returns a line that is parallel to l and separated from it
+// by the absolute value of distance. If distance is positive, the offset
+// curve is on the left side of l (looking along the direction of l), and
+// if it is negative, the offset curve is on the right side. If l is
+// closed, the offset curve is closed as well.
+//
+// Where l bends away from the offset side, the offset segments are joined
+// as specified by opts. Where it bends toward the offset side, the offset
+// segments are trimmed where they cross, and any loops that form because
+// the offset distance is larger than the local curvature of l are
+// removed.
+func (l LineString) OffsetCurve(distance float64, opts OffsetOptions) LineString {
+	if opts.QuadrantSegments <= 0 {
+		opts.QuadrantSegments = 8
+	}
+	if opts.MiterLimit <= 0 {
+		opts.MiterLimit = 5
+	}
+	var pts LineString
+	for _, p := range l {
+		pts = appendNew(pts, p)
+	}
+	if len(pts) < 2 || distance == 0 {
+		return pts
+	}
+	closed := len(pts) > 2 && pts[0].Equals(pts[len(pts)-1])
+
+	// Offset each segment along its left-hand normal.
+	n := len(pts) - 1
+	starts, ends := make([]Point, n), make([]Point, n)
+	for i := 0; i < n; i++ {
+		v := pointSubtract(pts[i+1], pts[i])
+		length := norm(v)
+		off := Point{X: -v.Y / length * distance, Y: v.X / length * distance}
+		starts[i] = Point{X: pts[i].X + off.X, Y: pts[i].Y + off.Y}
+		ends[i] = Point{X: pts[i+1].X + off.X, Y: pts[i+1].Y + off.Y}
+	}
+
+	var o LineString
+	if closed {
+		o = append(o, offsetJoin(pts[n-1], pts[0], pts[1],
+			segment{starts[n-1], ends[n-1]}, segment{starts[0], ends[0]}, distance, opts)...)
+	} else {
+		o = append(o, starts[0])
+	}
+	for i := 1; i < n; i++ {
+		for _, p := range offsetJoin(pts[i-1], pts[i], pts[i+1],
+			segment{starts[i-1], ends[i-1]}, segment{starts[i], ends[i]}, distance, opts) {
+			o = appendNew(o, p)
+		}
+	}
+	if closed {
+		o = appendNew(o, o[0])
+	} else {
+		o = appendNew(o, ends[n-1])
+	}
+	return removeOffsetLoops(o, l, math.Abs(distance))
+}
+
+// offsetJoin returns the points that join offset segments seg0 and seg1,
+// which are on either side of vertex v, which is between vertices prev
+// and next.
+func offsetJoin(prev, v, next Point, seg0, seg1 segment, distance float64, opts OffsetOptions) []Point {
+	e, s := seg0.end, seg1.start
+	turn := orient(prev, v, next)
+	d0, d1 := pointSubtract(v, prev), pointSubtract(next, v)
+	if turn == 0 && dot(d0, d1) > 0 {
+		return []Point{e} // The segments are collinear.
+	}
+	if turn != 0 && (turn > 0) == (distance > 0) {
+		// This is an inside corner, so the offset segments usually cross.
+		// If they don't, the loop that is formed is removed later.
+		if n, p, _ := findIntersection(seg0, seg1); n == 1 {
+			return []Point{p}
+		}
+		return []Point{e, s}
+	}
+	r := math.Abs(distance)
+	switch opts.Join {
+	case MiterJoin:
+		if turn != 0 {
+			m := lineIntersection(e, d0, s, d1)
+			if d(m, v) <= opts.MiterLimit*r {
+				return []Point{m}
+			}
+		}
+		return []Point{e, s}
+	case BevelJoin:
+		return []Point{e, s}
+	default:
+		a0 := math.Atan2(e.Y-v.Y, e.X-v.X)
+		a1 := math.Atan2(s.Y-v.Y, s.X-v.X)
+		// Go around the outside of the corner.
+		sweep := a1 - a0
+		if distance > 0 {
+			for sweep > 0 {
+				sweep -= 2 * math.Pi
+			}
+		} else {
+			for sweep < 0 {
+				sweep += 2 * math.Pi
+			}
+		}
+		steps := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2 / float64(opts.QuadrantSegments))))
+		o := []Point{e}
+		for k := 1; k < steps; k++ {
+			sin, cos := math.Sincos(a0 + sweep*float64(k)/float64(steps))
+			o = append(o, Point{X: v.X + r*cos, Y: v.Y + r*sin})
+		}
+		return append(o, s)
+	}
+}
+
+// lineIntersection returns the intersection of the line through p0 in
+// direction d0 and the line through p1 in direction d1, which must not be
+// parallel.
+func lineIntersection(p0, d0, p1, d1 Point) Point {
+	e := pointSubtract(p1, p0)
+	s := (e.X*d1.Y - e.Y*d1.X) / (d0.X*d1.Y - d0.Y*d1.X)
+	return Point{X: p0.X + s*d0.X, Y: p0.Y + s*d0.Y}
+}
+
+// removeOffsetLoops removes the loops from offset curve o of line l where
+// o crosses itself and all of the vertices within the loop are closer to l
+// than the offset distance r. Crossings that are caused by l crossing
+// itself are retained.
+func removeOffsetLoops(o, l LineString, r float64) LineString {
+	grid := newSegmentGrid(l, r)
+	// tooClose caches whether each vertex is closer to l than r, and
+	// far[k] is the number of vertices before o[k] that are not.
+	tooClose := make(map[Point]bool)
+	var far []int
+	countFar := func() {
+		far = make([]int, len(o)+1)
+		for k, p := range o {
+			near, ok := tooClose[p]
+			if !ok {
+				near = grid.within(p, r*(1-1e-9))
+				tooClose[p] = near
+			}
+			far[k+1] = far[k]
+			if !near {
+				far[k+1]++
+			}
+		}
+	}
+	countFar()
+	for i := 0; i < len(o)-1; i++ {
+		bi := NewBoundsPoint(o[i])
+		bi.extendPoint(o[i+1])
+		// All of the vertices in a loop are too close to l, so the loop
+		// must end before the next vertex that is not.
+		last := sort.Search(len(o), func(k int) bool { return far[k+1] > far[i+1] }) - 1
+		if last > len(o)-2 {
+			last = len(o) - 2
+		}
+		for j := last; j > i+1; j-- {
+			if o[0].Equals(o[len(o)-1]) && i == 0 && j == len(o)-2 {
+				continue // The first and last segments of a closed curve.
+			}
+			bj := NewBoundsPoint(o[j])
+			bj.extendPoint(o[j+1])
+			if !bi.Overlaps(bj) {
+				continue
+			}
+			n, p, _ := findIntersection(segment{o[i], o[i+1]}, segment{o[j], o[j+1]})
+			if n != 1 {
+				continue
+			}
+			o = append(append(o[:i+1:i+1], p), o[j+1:]...)
+			countFar()
+			break
+		}
+	}
+	return o
+}
+
+// segmentGrid is a uniform grid that holds the segments of a line, for
+// finding whether points are within a given distance of the line.
+type segmentGrid struct {
+	l     LineString
+	size  float64
+	cells map[[2]int][]int
+}
+
+// newSegmentGrid returns a grid that holds the segments of l, and can be
+// used to find whether points are within distance r of l.
+func newSegmentGrid(l LineString, r float64) *segmentGrid {
+	// Use cells that are at least as large as the average segment, so that
+	// each segment is only in a few cells.
+	var length float64
+	for i := 0; i < len(l)-1; i++ {
+		length += d(l[i], l[i+1])
+	}
+	g := &segmentGrid{
+		l:     l,
+		size:  math.Max(r, length/float64(len(l)-1)),
+		cells: make(map[[2]int][]int),
+	}
+	for i := 0; i < len(l)-1; i++ {
+		// Put each segment in all of the cells that are within r of it.
+		x0, y0 := g.cell(Point{X: math.Min(l[i].X, l[i+1].X) - r, Y: math.Min(l[i].Y, l[i+1].Y) - r})
+		x1, y1 := g.cell(Point{X: math.Max(l[i].X, l[i+1].X) + r, Y: math.Max(l[i].Y, l[i+1].Y) + r})
+		for x := x0; x <= x1; x++ {
+			for y := y0; y <= y1; y++ {
+				g.cells[[2]int{x, y}] = append(g.cells[[2]int{x, y}], i)
+			}
+		}
+	}
+	return g
+}
+
+// cell returns the column and row of the cell that contains p.
+func (g *segmentGrid) cell(p Point) (int, int) {
+	return int(math.Floor(p.X / g.size)), int(math.Floor(p.Y / g.size))
+}
+
+// within returns whether p is closer than dist to the line, where dist must
+// not be greater than the distance the grid was created for.
+func (g *segmentGrid) within(p Point, dist float64) bool {
+	x, y := g.cell(p)
+	for _, i := range g.cells[[2]int{x, y}] {
+		q := interpolateSegment(g.l[i], g.l[i+1], projectSegment(p, g.l[i], g.l[i+1]))
+		if d(p, q) < dist {
+			return true
+		}
+	}
+	return false
+}
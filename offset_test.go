@@ -0,0 +1,67 @@
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestOffsetCurve(t *testing.T) {
+	l := LineString{{0, 0}, {10, 0}, {10, 10}}
+	square := LineString{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	tests := []struct {
+		l        LineString
+		distance float64
+		opts     OffsetOptions
+		want     LineString
+	}{
+		{l: l, distance: 1, want: LineString{{0, 1}, {9, 1}, {9, 10}}},
+		{l: l, distance: -1, opts: OffsetOptions{Join: MiterJoin}, want: LineString{{0, -1}, {11, -1}, {11, 10}}},
+		{l: l, distance: -1, opts: OffsetOptions{Join: BevelJoin}, want: LineString{{0, -1}, {10, -1}, {11, 0}, {11, 10}}},
+		{
+			// The miter point is too far from the corner.
+			l:        LineString{{0, 0}, {10, 0}, {0, 1}},
+			distance: -1,
+			opts:     OffsetOptions{Join: MiterJoin},
+			want:     LineString{{0, -1}, {10, -1}, {10 + 1/math.Sqrt(101), 10 / math.Sqrt(101)}, {1 / math.Sqrt(101), 1 + 10/math.Sqrt(101)}},
+		},
+		{
+			// The offset of the short middle segment forms a loop.
+			l:        LineString{{0, 0}, {10, 0}, {10, 1}, {20, 1}},
+			distance: -2,
+			opts:     OffsetOptions{Join: MiterJoin},
+			want:     LineString{{0, -2}, {12, -2}, {12, -1}, {20, -1}},
+		},
+		{l: square, distance: -1, opts: OffsetOptions{Join: MiterJoin}, want: LineString{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}, {-1, -1}}},
+		{l: square, distance: 1, want: LineString{{1, 1}, {9, 1}, {9, 9}, {1, 9}, {1, 1}}},
+		{l: LineString{{0, 0}, {5, 0}, {10, 0}}, distance: 2, want: LineString{{0, 2}, {5, 2}, {10, 2}}},
+	}
+	for i, test := range tests {
+		have := test.l.OffsetCurve(test.distance, test.opts)
+		if !pointsSimilar(have, test.want, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestOffsetCurveRound(t *testing.T) {
+	l := LineString{{0, 0}, {10, 0}, {10, 10}}
+	have := l.OffsetCurve(-1, OffsetOptions{QuadrantSegments: 2})
+	want := LineString{{0, -1}, {10, -1}, {10 + math.Sqrt2/2, -math.Sqrt2 / 2}, {11, 0}, {11, 10}}
+	if !pointsSimilar(have, want, 1e-12) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+	// All vertices of the round join should be on the circle around the
+	// corner.
+	for _, p := range l.OffsetCurve(-1, OffsetOptions{})[1:] {
+		if p.Y > 0 {
+			break
+		}
+		if dist := d(p, Point{10, 0}); math.Abs(dist-1) > 1e-12 {
+			t.Errorf("%v: have distance %g, want 1", p, dist)
+		}
+	}
+	if have := (LineString{{1, 1}}).OffsetCurve(1, OffsetOptions{}); !reflect.DeepEqual(have, LineString{{1, 1}}) {
+		t.Errorf("single point: have %v", have)
+	}
+}
@@ -397,17 +397,7 @@ func pointOnSegment(p, segStart, segEnd geom.Point) bool {
 }
 
 // Distance returns the distance between the closest parts of two geometries.
-// Currently, only points are supported.
+// It is equivalent to geom.Distance.
 func Distance(a, b geom.Geom) float64 {
-	switch a.(type) {
-	case geom.Point:
-		switch b.(type) {
-		case geom.Point:
-			return d(a.(geom.Point), b.(geom.Point))
-		default:
-			panic("only points are currently supported")
-		}
-	default:
-		panic("only points are currently supported")
-	}
+	return geom.Distance(a, b)
 }
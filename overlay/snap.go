@@ -0,0 +1,52 @@
+package overlay
+
+import (
+	"github.com/ctessum/geom"
+	"github.com/ctessum/geom/index/rtree"
+)
+
+// indexed is a polygon stored in a spatial index.
+type indexed struct {
+	geom.Polygonal
+	i int
+}
+
+// SnapPolygons snaps each of the polygons in polys to the others, as
+// described for geom.Snap, so that neighboring polygons whose boundaries
+// almost line up share them exactly. The polygons are snapped in order,
+// and each one is snapped to the already snapped versions of the polygons
+// before it and the original versions of the polygons after it. Only the
+// polygons whose bounding boxes are within tolerance of each other are
+// compared.
+func SnapPolygons(polys []geom.Polygonal, tolerance float64) []geom.Polygonal {
+	tree := rtree.NewTree(25, 50)
+	for i, p := range polys {
+		if p != nil {
+			tree.Insert(&indexed{Polygonal: p, i: i})
+		}
+	}
+	o := make([]geom.Polygonal, len(polys))
+	copy(o, polys)
+	for i, p := range polys {
+		if p == nil {
+			continue
+		}
+		b := p.Bounds()
+		// Snapped vertices move by up to tolerance, so candidates that
+		// have already been snapped may be up to twice as far away.
+		search := &geom.Bounds{
+			Min: geom.Point{X: b.Min.X - 2*tolerance, Y: b.Min.Y - 2*tolerance},
+			Max: geom.Point{X: b.Max.X + 2*tolerance, Y: b.Max.Y + 2*tolerance},
+		}
+		var reference geom.GeometryCollection
+		for _, c := range tree.SearchIntersect(search) {
+			if j := c.(*indexed).i; j != i {
+				reference = append(reference, o[j])
+			}
+		}
+		if len(reference) > 0 {
+			o[i] = geom.Snap(p, reference, tolerance).(geom.Polygonal)
+		}
+	}
+	return o
+}
@@ -0,0 +1,132 @@
+// Package overlay carries out overlay operations on large sets of
+// geometries.
+package overlay
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/ctessum/geom"
+)
+
+// strNodeCapacity is the number of items in each node of the
+// sort-tile-recursive tree used to group polygons.
+const strNodeCapacity = 10
+
+// UnaryUnion returns the union of all of the polygons in polys.
+//
+// Instead of adding the polygons to the result one at a time, which gets
+// slower as the result grows, UnaryUnion uses cascaded union: the polygons
+// are arranged in the same way as the leaves of a sort-tile-recursive
+// (STR) packed R-tree, so that polygons that are near each other are
+// adjacent, and then unioned in pairs, pairs of pairs, and so on. Unions
+// of independent pairs are carried out in parallel, and groups whose
+// bounding boxes do not overlap are combined without any calculations.
+func UnaryUnion(polys []geom.Polygonal) geom.Polygonal {
+	items := make([]unionItem, 0, len(polys))
+	for _, p := range polys {
+		if p == nil {
+			continue
+		}
+		b := p.Bounds()
+		if b.Empty() {
+			continue
+		}
+		items = append(items, unionItem{p: p, b: b})
+	}
+	if len(items) == 0 {
+		return geom.MultiPolygon{}
+	}
+	strOrder(items)
+	sem := make(chan struct{}, runtime.GOMAXPROCS(-1))
+	return cascade(items, sem).p
+}
+
+// unionItem is a polygon together with its bounds.
+type unionItem struct {
+	p geom.Polygonal
+	b *geom.Bounds
+}
+
+// strOrder sorts items in the order of the leaves of a sort-tile-recursive
+// packed R-tree: the items are sorted into vertical slices by the x
+// coordinates of their centers, and then within each slice by the y
+// coordinates of their centers.
+func strOrder(items []unionItem) {
+	center := func(b *geom.Bounds) geom.Point {
+		return geom.Point{X: (b.Min.X + b.Max.X) / 2, Y: (b.Min.Y + b.Max.Y) / 2}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return center(items[i].b).X < center(items[j].b).X
+	})
+	nLeaves := int(math.Ceil(float64(len(items)) / strNodeCapacity))
+	nSlices := int(math.Ceil(math.Sqrt(float64(nLeaves))))
+	sliceSize := nSlices * strNodeCapacity
+	for start := 0; start < len(items); start += sliceSize {
+		end := start + sliceSize
+		if end > len(items) {
+			end = len(items)
+		}
+		slice := items[start:end]
+		sort.SliceStable(slice, func(i, j int) bool {
+			return center(slice[i].b).Y < center(slice[j].b).Y
+		})
+	}
+}
+
+// cascade returns the union of items, unioning the two halves of items
+// in parallel if a slot in sem is available.
+func cascade(items []unionItem, sem chan struct{}) unionItem {
+	if len(items) == 1 {
+		return items[0]
+	}
+	mid := len(items) / 2
+	var left, right unionItem
+	select {
+	case sem <- struct{}{}:
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			left = cascade(items[:mid], sem)
+			<-sem
+			wg.Done()
+		}()
+		right = cascade(items[mid:], sem)
+		wg.Wait()
+	default:
+		left = cascade(items[:mid], sem)
+		right = cascade(items[mid:], sem)
+	}
+	return unionPair(left, right)
+}
+
+// unionPair returns the union of a and b.
+func unionPair(a, b unionItem) unionItem {
+	bounds := a.b.Copy()
+	bounds.Extend(b.b)
+	if !a.b.Overlaps(b.b) {
+		// The polygons can't overlap or share edges, so they can be
+		// combined directly.
+		mp := append(append(geom.MultiPolygon{}, a.p.Polygons()...), b.p.Polygons()...)
+		return unionItem{p: mp, b: bounds}
+	}
+	return unionItem{p: a.p.Union(b.p), b: bounds}
+}
+
+// Dissolve groups polys by the key returned by keyFunc for the index of
+// each polygon, and returns the union of each group. This can be used,
+// for example, to combine counties into states.
+func Dissolve(polys []geom.Polygonal, keyFunc func(i int) string) map[string]geom.Polygonal {
+	groups := make(map[string][]geom.Polygonal)
+	for i, p := range polys {
+		k := keyFunc(i)
+		groups[k] = append(groups[k], p)
+	}
+	o := make(map[string]geom.Polygonal, len(groups))
+	for k, g := range groups {
+		o[k] = UnaryUnion(g)
+	}
+	return o
+}
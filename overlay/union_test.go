@@ -0,0 +1,106 @@
+package overlay
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ctessum/geom"
+)
+
+func square(x, y float64) geom.Polygon {
+	return geom.Polygon{{{X: x, Y: y}, {X: x + 1, Y: y}, {X: x + 1, Y: y + 1}, {X: x, Y: y + 1}, {X: x, Y: y}}}
+}
+
+func TestUnaryUnion(t *testing.T) {
+	var polys []geom.Polygonal
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 20; j++ {
+			polys = append(polys, square(float64(i), float64(j)))
+		}
+	}
+	// An overlapping polygon and a separate polygon.
+	polys = append(polys,
+		geom.Polygon{{{X: 19.5, Y: 19.5}, {X: 21, Y: 19.5}, {X: 21, Y: 21}, {X: 19.5, Y: 21}}},
+		&geom.Bounds{Min: geom.Point{X: 30, Y: 30}, Max: geom.Point{X: 31, Y: 31}},
+		nil,
+	)
+	u := UnaryUnion(polys)
+	wantArea := 400 + 2.25 - 0.25 + 1
+	if a := u.Area(); math.Abs(a-wantArea) > 1e-9 {
+		t.Errorf("area: have %g, want %g", a, wantArea)
+	}
+	var rings int
+	for _, p := range u.Polygons() {
+		rings += len(p)
+	}
+	if rings != 2 {
+		t.Errorf("have %d rings, want 2", rings)
+	}
+	for _, p := range []geom.Point{{X: 10, Y: 10}, {X: 20.5, Y: 20.5}, {X: 30.5, Y: 30.5}} {
+		if p.Within(u) != geom.Inside {
+			t.Errorf("%v should be inside the union", p)
+		}
+	}
+}
+
+func TestUnaryUnionEmpty(t *testing.T) {
+	if a := UnaryUnion(nil).Area(); a != 0 {
+		t.Errorf("have area %g, want 0", a)
+	}
+}
+
+func TestDissolve(t *testing.T) {
+	var polys []geom.Polygonal
+	for i := 0; i < 4; i++ {
+		polys = append(polys, square(float64(i), 0))
+	}
+	d := Dissolve(polys, func(i int) string { return fmt.Sprint(i / 2) })
+	if len(d) != 2 {
+		t.Fatalf("have %d groups, want 2", len(d))
+	}
+	for k, p := range d {
+		if a := p.Area(); math.Abs(a-2) > 1e-9 {
+			t.Errorf("group %s: have area %g, want 2", k, a)
+		}
+		if len(p.Polygons()) != 1 || len(p.Polygons()[0]) != 1 {
+			t.Errorf("group %s: have %v, want a single ring", k, p)
+		}
+	}
+}
+
+func TestSnapPolygons(t *testing.T) {
+	polys := []geom.Polygonal{
+		square(0, 0),
+		geom.Polygon{{{X: 1.05, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 0.98, Y: 1}, {X: 1.05, Y: 0}}},
+		geom.Polygon{{{X: 0, Y: 1.02}, {X: 1.5, Y: 1.03}, {X: 0, Y: 2}, {X: 0, Y: 1.02}}},
+		nil,
+		square(10, 10),
+	}
+	snapped := SnapPolygons(polys, 0.1)
+	if len(snapped) != len(polys) {
+		t.Fatalf("have %d polygons, want %d", len(snapped), len(polys))
+	}
+	if snapped[3] != nil {
+		t.Errorf("nil polygon: have %v", snapped[3])
+	}
+	if !snapped[4].Similar(polys[4], 1e-12) {
+		t.Errorf("separate polygon changed: have %v", snapped[4])
+	}
+	// The snapped polygons should fit together without gaps or overlaps.
+	var area float64
+	for _, p := range snapped[:3] {
+		area += p.Area()
+	}
+	u := UnaryUnion(snapped[:3])
+	if a := u.Area(); math.Abs(a-area) > 1e-9 {
+		t.Errorf("union area: have %g, want %g", a, area)
+	}
+	var rings int
+	for _, p := range u.Polygons() {
+		rings += len(p)
+	}
+	if rings != 1 {
+		t.Errorf("have %d rings, want 1: %v", rings, u)
+	}
+}
@@ -124,3 +124,7 @@ func (p Polygon) Within(poly Polygonal) WithinStatus {
 	}
 	return Inside
 }
+
+// Distance calculates the shortest distance from pt to the Polygon. It is
+// zero if pt is inside of the Polygon.
+func (p Polygon) Distance(pt Point) float64 { return Distance(p, pt) }
@@ -0,0 +1,244 @@
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+// Polygonize returns the polygons formed by the closed rings in lines.
+// The linestrings in lines must be noded, meaning that they may only
+// touch each other at their endpoints (as in the output of Node).
+// Linestrings that do not form part of a ring (dangles, or cut edges
+// that connect two otherwise separate rings) are ignored. Every enclosed area
+// is returned as a separate polygon, so rings nested inside of other
+// rings are returned both as holes in the enclosing polygon and as
+// polygons in their own right.
+func Polygonize(lines MultiLineString) MultiPolygon {
+	var edges []*halfEdge
+	for _, l := range lines {
+		if len(l) < 2 {
+			continue
+		}
+		r := make(LineString, len(l))
+		for i, p := range l {
+			r[len(l)-1-i] = p
+		}
+		e := &halfEdge{points: l}
+		t := &halfEdge{points: r, twin: e}
+		e.twin = t
+		edges = append(edges, e, t)
+	}
+
+	var rings []LineString
+	for {
+		pruneDangles(edges)
+		var faces [][]*halfEdge
+		rings, faces = traverseFaces(edges)
+		// Remove cut edges, where both sides of the edge are
+		// part of the same face.
+		cut := false
+		for i, face := range faces {
+			for _, e := range face {
+				if e.twin.face == i {
+					e.removed, e.twin.removed = true, true
+					cut = true
+				}
+			}
+		}
+		if !cut {
+			break
+		}
+	}
+
+	var shells, holes []LineString
+	for _, r := range rings {
+		if signedarea(r) > 0 {
+			shells = append(shells, r)
+		} else {
+			holes = append(holes, r)
+		}
+	}
+	o := make(MultiPolygon, len(shells))
+	for i, s := range shells {
+		o[i] = Polygon{Path(s)}
+	}
+	// Holes are the outer boundaries of rings that are nested within
+	// other rings, so they are assigned to the smallest enclosing shell.
+	for _, h := range holes {
+		shell := -1
+		var shellArea float64
+		for i, s := range shells {
+			sp := Polygon{Path(s)}
+			if pointInPolygon(h[0], sp, sp.ringBounds()) != Inside {
+				continue
+			}
+			if a := signedarea(s); shell < 0 || a < shellArea {
+				shell = i
+				shellArea = a
+			}
+		}
+		if shell >= 0 {
+			o[shell] = append(o[shell], Path(h))
+		}
+	}
+	return o
+}
+
+// Split divides p into pieces along l. The parts of l that are
+// outside of p, or that do not fully cross p, have no effect on the
+// result.
+func Split(p Polygonal, l LineString) MultiPolygon {
+	var lines MultiLineString
+	for _, poly := range p.Polygons() {
+		for _, r := range poly {
+			if len(r) == 0 {
+				continue
+			}
+			ring := append(LineString{}, r...)
+			if !ring[0].Equals(ring[len(ring)-1]) {
+				ring = append(ring, ring[0])
+			}
+			lines = append(lines, ring)
+		}
+	}
+	lines = append(lines, l)
+	var o MultiPolygon
+	for _, face := range Polygonize(Node(lines)) {
+		if pointInPolygonal(interiorPoint(face), p) == Inside {
+			o = append(o, face)
+		}
+	}
+	return o
+}
+
+// halfEdge is one direction of an edge in a planar graph.
+type halfEdge struct {
+	points  LineString
+	twin    *halfEdge
+	next    *halfEdge
+	face    int
+	removed bool
+}
+
+// angle returns the direction in which e leaves its origin.
+func (e *halfEdge) angle() float64 {
+	return math.Atan2(e.points[1].Y-e.points[0].Y, e.points[1].X-e.points[0].X)
+}
+
+// pruneDangles repeatedly removes the edges that have an end point
+// that is not connected to any other edge.
+func pruneDangles(edges []*halfEdge) {
+	for {
+		degree := make(map[Point]int)
+		for _, e := range edges {
+			if !e.removed {
+				degree[e.points[0]]++
+			}
+		}
+		pruned := false
+		for _, e := range edges {
+			if !e.removed && degree[e.points[0]] == 1 {
+				e.removed, e.twin.removed = true, true
+				pruned = true
+			}
+		}
+		if !pruned {
+			return
+		}
+	}
+}
+
+// traverseFaces links each half edge to the next one around the face to its
+// left and returns the boundary of each face, along with the half edges that
+// make it up. Faces to the left of counter-clockwise rings are bounded, and
+// the remaining faces are the outer boundaries of connected groups of edges.
+func traverseFaces(edges []*halfEdge) ([]LineString, [][]*halfEdge) {
+	out := make(map[Point][]*halfEdge)
+	for _, e := range edges {
+		if !e.removed {
+			out[e.points[0]] = append(out[e.points[0]], e)
+			e.face = -1
+		}
+	}
+	for _, es := range out {
+		sort.Slice(es, func(i, j int) bool { return es[i].angle() < es[j].angle() })
+		for i, e := range es {
+			// The next edge around the face that is to the left of e.twin is
+			// the next edge clockwise around their shared node.
+			if i == 0 {
+				e.twin.next = es[len(es)-1]
+			} else {
+				e.twin.next = es[i-1]
+			}
+		}
+	}
+
+	var rings []LineString
+	var faces [][]*halfEdge
+	for _, e := range edges {
+		if e.removed || e.face >= 0 {
+			continue
+		}
+		var ring LineString
+		var face []*halfEdge
+		for f := e; f.face < 0; f = f.next {
+			f.face = len(rings)
+			face = append(face, f)
+			for _, p := range f.points {
+				ring = appendNew(ring, p)
+			}
+		}
+		rings = append(rings, ring)
+		faces = append(faces, face)
+	}
+	return rings, faces
+}
+
+// interiorPoint returns a point that is strictly inside of p, which must
+// have a non-zero area.
+func interiorPoint(p Polygon) Point {
+	// Choose a horizontal line near the middle of the polygon that does
+	// not pass through any vertices.
+	var ys []float64
+	for _, r := range p {
+		for _, pp := range r {
+			ys = append(ys, pp.Y)
+		}
+	}
+	sort.Float64s(ys)
+	mid := (ys[0] + ys[len(ys)-1]) / 2
+	k := sort.Search(len(ys), func(i int) bool { return ys[i] > mid })
+	if k == len(ys) {
+		k--
+	}
+	hi := ys[k]
+	lo := ys[0]
+	for i := k - 1; i >= 0; i-- {
+		if ys[i] < hi {
+			lo = ys[i]
+			break
+		}
+	}
+	y := (lo + hi) / 2
+
+	// Find where the line crosses the polygon edges, and return the
+	// middle of the widest section that is inside of the polygon.
+	var xs []float64
+	for _, r := range p {
+		for i := range r {
+			a, b := r[i], r[(i+1)%len(r)]
+			if (a.Y > y) != (b.Y > y) {
+				xs = append(xs, a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+			}
+		}
+	}
+	sort.Float64s(xs)
+	var x, width float64
+	for i := 0; i+1 < len(xs); i += 2 {
+		if w := xs[i+1] - xs[i]; w > width {
+			width = w
+			x = (xs[i] + xs[i+1]) / 2
+		}
+	}
+	return Point{X: x, Y: y}
+}
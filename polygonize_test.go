@@ -0,0 +1,86 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonize(t *testing.T) {
+	square := func(x0, y0, x1, y1 float64) LineString {
+		return LineString{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}, {X: x0, Y: y0}}
+	}
+	tests := []struct {
+		name  string
+		lines MultiLineString
+		areas []float64
+	}{
+		{
+			name: "cross",
+			lines: MultiLineString{
+				square(0, 0, 2, 2),
+				{{X: 1, Y: -1}, {X: 1, Y: 3}},
+				{{X: -1, Y: 1}, {X: 3, Y: 1}},
+			},
+			areas: []float64{1, 1, 1, 1},
+		},
+		{
+			name: "nested",
+			lines: MultiLineString{
+				square(0, 0, 4, 4),
+				square(1, 1, 2, 2),
+			},
+			areas: []float64{15, 1},
+		},
+		{
+			name: "cut edge",
+			lines: MultiLineString{
+				square(0, 0, 4, 4),
+				square(1, 1, 2, 2),
+				{{X: 0, Y: 0}, {X: 1, Y: 1}},
+			},
+			areas: []float64{15, 1},
+		},
+		{
+			name:  "dangle",
+			lines: MultiLineString{square(0, 0, 1, 1), {{X: 1, Y: 1}, {X: 2, Y: 2}}},
+			areas: []float64{1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			polys := Polygonize(Node(test.lines))
+			if len(polys) != len(test.areas) {
+				t.Fatalf("have %d polygons, want %d: %v", len(polys), len(test.areas), polys)
+			}
+			var have, want float64
+			for i, p := range polys {
+				have += p.Area()
+				want += test.areas[i]
+			}
+			if math.Abs(have-want) > 1.e-9 {
+				t.Errorf("total area: have %g, want %g", have, want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	p := Polygon{
+		{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+		{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}},
+	}
+	pieces := Split(p, LineString{{X: 3, Y: -1}, {X: 3, Y: 5}})
+	if len(pieces) != 2 {
+		t.Fatalf("have %d pieces, want 2: %v", len(pieces), pieces)
+	}
+	areas := []float64{pieces[0].Area(), pieces[1].Area()}
+	if !(areas[0] == 11 && areas[1] == 4 || areas[0] == 4 && areas[1] == 11) {
+		t.Errorf("areas: have %v, want [11 4]", areas)
+	}
+
+	// A line that does not cross the polygon does not split it.
+	pieces = Split(p, LineString{{X: 5, Y: -1}, {X: 5, Y: 5}})
+	if len(pieces) != 1 || pieces[0].Area() != 15 {
+		t.Errorf("have %v, want original polygon", pieces)
+	}
+}
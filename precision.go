@@ -0,0 +1,498 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ctessum/polyclip-go"
+)
+
+// PrecisionType specifies how coordinates are represented in a
+// PrecisionModel.
+type PrecisionType int
+
+const (
+	// FloatingPrecision represents coordinates as full-precision
+	// float64 values.
+	FloatingPrecision PrecisionType = iota
+
+	// FixedPrecision represents coordinates as multiples of a grid size.
+	FixedPrecision
+
+	// Float32Precision represents coordinates as float32 values.
+	Float32Precision
+)
+
+// PrecisionModel specifies the precision with which coordinates are
+// represented.
+type PrecisionModel struct {
+	Type PrecisionType
+
+	// GridSize is the spacing between the allowed coordinate values
+	// when Type is FixedPrecision.
+	GridSize float64
+}
+
+// SnapToGrid returns a copy of g with its coordinates rounded to the
+// nearest multiple of gridSize. See PrecisionModel.Reduce for how collapsed
+// parts of g are handled.
+func SnapToGrid(g Geom, gridSize float64) Geom {
+	return PrecisionModel{Type: FixedPrecision, GridSize: gridSize}.Reduce(g)
+}
+
+// MakePrecise rounds p to the precision of pm.
+func (pm PrecisionModel) MakePrecise(p Point) Point {
+	switch pm.Type {
+	case FloatingPrecision:
+		return p
+	case FixedPrecision:
+		if pm.GridSize <= 0 {
+			panic(fmt.Errorf("geom: invalid grid size %g", pm.GridSize))
+		}
+		// Multiplying by an integer scale factor avoids introducing
+		// rounding error for grid sizes such as 0.1.
+		if scale := 1 / pm.GridSize; scale == math.Round(scale) {
+			return Point{X: math.Round(p.X*scale) / scale, Y: math.Round(p.Y*scale) / scale}
+		}
+		return Point{X: math.Round(p.X/pm.GridSize) * pm.GridSize, Y: math.Round(p.Y/pm.GridSize) * pm.GridSize}
+	case Float32Precision:
+		return Point{X: float64(float32(p.X)), Y: float64(float32(p.Y))}
+	default:
+		panic(fmt.Errorf("geom: invalid precision type %d", pm.Type))
+	}
+}
+
+// Reduce returns a copy of g with its coordinates rounded to the precision
+// of pm. Repeated points that result from the rounding are removed.
+// Polygon rings that collapse to fewer than three distinct points are
+// removed, and polygons whose outer ring collapses are removed entirely.
+// The result has the same type as g, except that *Bounds are returned as
+// *Bounds with rounded corners, and other Polygonal and Linear types, such
+// as FlatMultiLineString, are returned as MultiPolygons and
+// MultiLineStrings. For Z and M types, only the X and Y coordinates are
+// rounded, and points are compared by their X and Y coordinates when
+// removing repeated points, keeping the Z coordinate or measure of the
+// first one.
+func (pm PrecisionModel) Reduce(g Geom) Geom {
+	switch g := g.(type) {
+	case Point:
+		return pm.MakePrecise(g)
+	case MultiPoint:
+		o := make(MultiPoint, len(g))
+		for i, p := range g {
+			o[i] = pm.MakePrecise(p)
+		}
+		return o
+	case LineString:
+		return LineString(pm.reducePath(Path(g)))
+	case MultiLineString:
+		o := make(MultiLineString, len(g))
+		for i, l := range g {
+			o[i] = LineString(pm.reducePath(Path(l)))
+		}
+		return o
+	case Polygon:
+		return pm.reducePolygon(g)
+	case MultiPolygon:
+		var o MultiPolygon
+		for _, p := range g {
+			if p = pm.reducePolygon(p); len(p) > 0 {
+				o = append(o, p)
+			}
+		}
+		return o
+	case *Bounds:
+		return &Bounds{Min: pm.MakePrecise(g.Min), Max: pm.MakePrecise(g.Max)}
+	case GeometryCollection:
+		o := make(GeometryCollection, len(g))
+		for i, gg := range g {
+			o[i] = pm.Reduce(gg)
+		}
+		return o
+	case PointZ:
+		return pm.makePreciseZ(g)
+	case MultiPointZ:
+		o := make(MultiPointZ, len(g))
+		for i, p := range g {
+			o[i] = pm.makePreciseZ(p)
+		}
+		return o
+	case LineStringZ:
+		return LineStringZ(pm.reducePathZ(PathZ(g)))
+	case MultiLineStringZ:
+		o := make(MultiLineStringZ, len(g))
+		for i, l := range g {
+			o[i] = LineStringZ(pm.reducePathZ(PathZ(l)))
+		}
+		return o
+	case PolygonZ:
+		return pm.reducePolygonZ(g)
+	case MultiPolygonZ:
+		var o MultiPolygonZ
+		for _, p := range g {
+			if p = pm.reducePolygonZ(p); len(p) > 0 {
+				o = append(o, p)
+			}
+		}
+		return o
+	case LineStringM:
+		return pm.reduceLineStringM(g)
+	case MultiLineStringM:
+		o := make(MultiLineStringM, len(g))
+		for i, l := range g {
+			o[i] = pm.reduceLineStringM(l)
+		}
+		return o
+	case Polygonal:
+		return pm.Reduce(MultiPolygon(g.Polygons()))
+	case Linear:
+		return pm.Reduce(linearLines(g))
+	default:
+		panic(fmt.Errorf("geom: unsupported type %T", g))
+	}
+}
+
+// reducePath rounds the points in p and removes repeated points.
+func (pm PrecisionModel) reducePath(p Path) Path {
+	o := make(LineString, 0, len(p))
+	for _, pt := range p {
+		o = appendNew(o, pm.MakePrecise(pt))
+	}
+	return Path(o)
+}
+
+// reducePolygon rounds the points in p and removes rings that collapse.
+func (pm PrecisionModel) reducePolygon(p Polygon) Polygon {
+	var o Polygon
+	for i, r := range p {
+		r = cleanRing(pm.reducePath(r))
+		if len(r) < 3 {
+			if i == 0 {
+				return Polygon{}
+			}
+			continue
+		}
+		o = append(o, r)
+	}
+	return o
+}
+
+// makePreciseZ rounds the X and Y coordinates of p to the precision of pm.
+func (pm PrecisionModel) makePreciseZ(p PointZ) PointZ {
+	xy := pm.MakePrecise(p.XY())
+	return PointZ{X: xy.X, Y: xy.Y, Z: p.Z}
+}
+
+// reducePathZ is the equivalent of reducePath for PathZs.
+func (pm PrecisionModel) reducePathZ(p PathZ) PathZ {
+	o := make(PathZ, 0, len(p))
+	for _, pt := range p {
+		pt = pm.makePreciseZ(pt)
+		if len(o) > 0 && o[len(o)-1].XY().Equals(pt.XY()) {
+			continue
+		}
+		o = append(o, pt)
+	}
+	return o
+}
+
+// reducePolygonZ is the equivalent of reducePolygon for PolygonZs.
+func (pm PrecisionModel) reducePolygonZ(p PolygonZ) PolygonZ {
+	var o PolygonZ
+	for i, r := range p {
+		r = pm.reducePathZ(r)
+		xy := Path(pointsXY(r))
+		var c PathZ
+		for _, k := range cleanRingIndices(xy) {
+			c = append(c, r[k])
+		}
+		if len(c) < 3 {
+			if i == 0 {
+				return PolygonZ{}
+			}
+			continue
+		}
+		if len(xy) > 1 && xy[0].Equals(xy[len(xy)-1]) {
+			c = append(c, c[0])
+		}
+		o = append(o, c)
+	}
+	return o
+}
+
+// reduceLineStringM is the equivalent of reducePath for LineStringMs.
+func (pm PrecisionModel) reduceLineStringM(l LineStringM) LineStringM {
+	o := make(LineStringM, 0, len(l))
+	for _, pt := range l {
+		xy := pm.MakePrecise(pt.XY())
+		if len(o) > 0 && o[len(o)-1].XY().Equals(xy) {
+			continue
+		}
+		o = append(o, PointM{X: xy.X, Y: xy.Y, M: pt.M})
+	}
+	return o
+}
+
+// cleanRing removes the closing point of r, if it has one, along with any
+// repeated points and spikes (where the ring doubles back on itself). If the
+// input ring was closed, the output ring is closed as well unless it has
+// collapsed.
+func cleanRing(r Path) Path {
+	var o Path
+	for _, i := range cleanRingIndices(r) {
+		o = append(o, r[i])
+	}
+	if len(o) >= 3 && len(r) > 1 && r[0].Equals(r[len(r)-1]) {
+		o = append(o, o[0])
+	}
+	return o
+}
+
+// cleanRingIndices returns the indices of the points of r that cleanRing
+// keeps, not including the closing point.
+func cleanRingIndices(r Path) []int {
+	var o []int
+	for i, pt := range openRing(r) {
+		if len(o) > 0 && pt.Equals(r[o[len(o)-1]]) {
+			continue
+		}
+		if len(o) > 1 && pt.Equals(r[o[len(o)-2]]) {
+			o = o[:len(o)-1]
+			continue
+		}
+		o = append(o, i)
+	}
+	// Remove spikes and repeated points that wrap around the start.
+	for len(o) > 2 {
+		if r[o[0]].Equals(r[o[len(o)-1]]) {
+			o = o[:len(o)-1]
+		} else if r[o[len(o)-2]].Equals(r[o[0]]) {
+			o = o[:len(o)-2]
+		} else if r[o[len(o)-1]].Equals(r[o[1]]) {
+			o = o[1 : len(o)-1]
+		} else {
+			break
+		}
+	}
+	return o
+}
+
+// Intersection returns the area(s) shared by a and b, with the inputs
+// snap-rounded to the precision of pm so that the result is topologically
+// consistent at that precision.
+func (pm PrecisionModel) Intersection(a, b Polygonal) Polygonal {
+	return pm.overlay(a, b, polyclip.INTERSECTION)
+}
+
+// Union returns the combination of a and b, with the inputs
+// snap-rounded to the precision of pm so that the result is topologically
+// consistent at that precision.
+func (pm PrecisionModel) Union(a, b Polygonal) Polygonal {
+	return pm.overlay(a, b, polyclip.UNION)
+}
+
+// XOr returns the area(s) occupied by either a or b but not both, with the
+// inputs snap-rounded to the precision of pm so that the result is
+// topologically consistent at that precision.
+func (pm PrecisionModel) XOr(a, b Polygonal) Polygonal {
+	return pm.overlay(a, b, polyclip.XOR)
+}
+
+// Difference subtracts b from a, with the inputs snap-rounded to the
+// precision of pm so that the result is topologically consistent at that
+// precision.
+func (pm PrecisionModel) Difference(a, b Polygonal) Polygonal {
+	return pm.overlay(a, b, polyclip.DIFFERENCE)
+}
+
+// overlay snap-rounds the rings of a and b together and then carries out
+// op on them. With FloatingPrecision, the inputs are used as they are.
+func (pm PrecisionModel) overlay(a, b Polygonal, op polyclip.Op) Polygonal {
+	var rings []Path
+	var na int
+	for i, p := range []Polygonal{a, b} {
+		for _, poly := range p.Polygons() {
+			for _, r := range poly {
+				if len(r) > 1 && !r[0].Equals(r[len(r)-1]) {
+					r = append(append(Path{}, r...), r[0])
+				}
+				rings = append(rings, r)
+			}
+		}
+		if i == 0 {
+			na = len(rings)
+		}
+	}
+	if pm.Type != FloatingPrecision {
+		rings = pm.snapRound(rings)
+	}
+	var pa, pb polyclip.Polygon
+	for i, r := range rings {
+		if r = cleanRing(r); len(r) < 3 {
+			continue
+		}
+		c := make(polyclip.Contour, len(r))
+		for j, pt := range r {
+			c[j] = polyclip.Point{X: pt.X, Y: pt.Y}
+		}
+		if i < na {
+			pa = append(pa, c)
+		} else {
+			pb = append(pb, c)
+		}
+	}
+	o := polyClipToPolygon(pa.Construct(op, pb))
+	if pm.Type == FloatingPrecision {
+		return o
+	}
+	// The result is a set of rings, so any ring may be removed if it
+	// has collapsed.
+	var out Polygon
+	for _, r := range o {
+		if r = cleanRing(pm.reducePath(r)); len(r) >= 3 {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// snapRound carries out snap rounding of the paths: all vertices and
+// intersection points are rounded to the precision of pm, creating
+// "hot pixels", and every segment that passes through a hot pixel is
+// routed through its center. Afterwards, segments from the paths only
+// touch each other at shared vertices or where they are identical.
+//
+// The algorithm is from J. D. Hobby, Practical segment intersection with
+// finite precision output. Comput. Geom. 13(4), 199–214 (1999).
+func (pm PrecisionModel) snapRound(paths []Path) []Path {
+	var segs []segment
+	for _, p := range paths {
+		for i := 0; i < len(p)-1; i++ {
+			segs = append(segs, segment{p[i], p[i+1]})
+		}
+	}
+
+	// Find the hot pixels.
+	hotSet := make(map[Point]struct{})
+	for _, s := range segs {
+		hotSet[pm.MakePrecise(s.start)] = struct{}{}
+	}
+	segBounds := make([]*Bounds, len(segs))
+	for i, s := range segs {
+		segBounds[i] = NewBoundsPoint(s.start).extendPoint(s.end)
+	}
+	for i, s1 := range segs {
+		for j := i + 1; j < len(segs); j++ {
+			if !segBounds[i].Overlaps(segBounds[j]) {
+				continue
+			}
+			n, p1, p2 := findIntersection(s1, segs[j])
+			if n > 0 {
+				hotSet[pm.MakePrecise(p1)] = struct{}{}
+			}
+			if n > 1 {
+				hotSet[pm.MakePrecise(p2)] = struct{}{}
+			}
+		}
+	}
+	hot := make([]Point, 0, len(hotSet))
+	for p := range hotSet {
+		hot = append(hot, p)
+	}
+	sort.Slice(hot, func(i, j int) bool { return hot[i].X < hot[j].X })
+	maxHalfWidth := 0.
+	for _, h := range hot {
+		if pb := pm.pixel(h); h.X-pb.Min.X > maxHalfWidth {
+			maxHalfWidth = h.X - pb.Min.X
+		}
+	}
+
+	// Route each segment through the hot pixels that it passes through.
+	o := make([]Path, len(paths))
+	for i, p := range paths {
+		if len(p) == 0 {
+			continue
+		}
+		l := LineString{pm.MakePrecise(p[0])}
+		for j := 0; j < len(p)-1; j++ {
+			s := segment{p[j], p[j+1]}
+			type hit struct {
+				p Point
+				t float64
+			}
+			var hits []hit
+			start := sort.Search(len(hot), func(k int) bool {
+				return hot[k].X >= math.Min(s.start.X, s.end.X)-maxHalfWidth
+			})
+			for k := start; k < len(hot) && hot[k].X <= math.Max(s.start.X, s.end.X)+maxHalfWidth; k++ {
+				if segmentIntersectsBounds(s, pm.pixel(hot[k])) {
+					hits = append(hits, hit{p: hot[k], t: projectSegment(hot[k], s.start, s.end)})
+				}
+			}
+			sort.Slice(hits, func(a, b int) bool { return hits[a].t < hits[b].t })
+			for _, h := range hits {
+				l = appendNew(l, h.p)
+			}
+			l = appendNew(l, pm.MakePrecise(s.end))
+		}
+		o[i] = Path(l)
+	}
+	return o
+}
+
+// pixel returns the area of points that are rounded to h, which must
+// already be rounded to the precision of pm.
+func (pm PrecisionModel) pixel(h Point) *Bounds {
+	switch pm.Type {
+	case FixedPrecision:
+		g := pm.GridSize / 2
+		return &Bounds{Min: Point{X: h.X - g, Y: h.Y - g}, Max: Point{X: h.X + g, Y: h.Y + g}}
+	case Float32Precision:
+		lo := func(x float64) float64 {
+			return x - (x-float64(math.Nextafter32(float32(x), float32(math.Inf(-1)))))/2
+		}
+		hi := func(x float64) float64 {
+			return x + (float64(math.Nextafter32(float32(x), float32(math.Inf(1))))-x)/2
+		}
+		return &Bounds{Min: Point{X: lo(h.X), Y: lo(h.Y)}, Max: Point{X: hi(h.X), Y: hi(h.Y)}}
+	default:
+		return NewBoundsPoint(h)
+	}
+}
+
+// segmentIntersectsBounds returns whether s crosses or touches b, using
+// Liang-Barsky clipping.
+func segmentIntersectsBounds(s segment, b *Bounds) bool {
+	t0, t1 := 0., 1.
+	dx, dy := s.end.X-s.start.X, s.end.Y-s.start.Y
+	for _, c := range [][2]float64{
+		{-dx, s.start.X - b.Min.X},
+		{dx, b.Max.X - s.start.X},
+		{-dy, s.start.Y - b.Min.Y},
+		{dy, b.Max.Y - s.start.Y},
+	} {
+		p, q := c[0], c[1]
+		if p == 0 {
+			if q < 0 {
+				return false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return false
+			} else if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return false
+			} else if r < t1 {
+				t1 = r
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSnapToGrid(t *testing.T) {
+	tests := []struct {
+		g, want Geom
+	}{
+		{g: Point{0.26, -0.34}, want: Point{0.3, -0.3}},
+		{g: MultiPoint{{0.26, 1.04}, {2, 3}}, want: MultiPoint{{0.3, 1}, {2, 3}}},
+		{
+			g:    LineString{{0, 0}, {0.01, 0.02}, {1.12, 0}},
+			want: LineString{{0, 0}, {1.1, 0}},
+		},
+		{
+			g: Polygon{
+				{{0.04, 0.04}, {1.01, 0}, {1, 1.01}, {0, 1}, {0.04, 0.04}},
+				{{0.5, 0.5}, {0.52, 0.5}, {0.52, 0.52}, {0.5, 0.5}},
+			},
+			want: Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+		{
+			g: MultiPolygon{
+				{{{0, 0}, {0.01, 0}, {0.01, 0.01}, {0, 0}}},
+				{{{2, 2}, {3, 2}, {3, 3}, {2.01, 3.01}, {2, 2}}},
+			},
+			want: MultiPolygon{{{{2, 2}, {3, 2}, {3, 3}, {2, 3}, {2, 2}}}},
+		},
+		{
+			// A spike that collapses onto the rest of the ring is removed.
+			g:    Polygon{{{0, 0}, {1, 0}, {1, 1}, {1.04, 2}, {1.01, 1}, {0, 1}, {0, 0}}},
+			want: Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+		{
+			g:    &Bounds{Min: Point{0.12, 0.19}, Max: Point{1.26, 1.33}},
+			want: &Bounds{Min: Point{0.1, 0.2}, Max: Point{1.3, 1.3}},
+		},
+		{g: PointZ{0.26, -0.34, 0.123}, want: PointZ{0.3, -0.3, 0.123}},
+		{
+			// Z coordinates and measures are kept, and repeated points are
+			// found by their X and Y coordinates.
+			g:    LineStringZ{{0, 0, 1.23}, {0.01, 0.02, 4.56}, {1.12, 0, 7.89}},
+			want: LineStringZ{{0, 0, 1.23}, {1.1, 0, 7.89}},
+		},
+		{
+			g:    MultiLineStringM{{{0, 0, 0.01}, {0.01, 0.02, 0.02}, {1.12, 0, 1.12}}},
+			want: MultiLineStringM{{{0, 0, 0.01}, {1.1, 0, 1.12}}},
+		},
+		{
+			g:    PolygonZ{{{0, 0, 5}, {1, 0, 5}, {1, 1, 5}, {1.04, 2, 9}, {1.01, 1, 5}, {0, 1, 5}, {0, 0, 5}}},
+			want: PolygonZ{{{0, 0, 5}, {1, 0, 5}, {1, 1, 5}, {0, 1, 5}, {0, 0, 5}}},
+		},
+		{
+			g: MultiPolygonZ{
+				{{{0, 0, 1}, {0.01, 0, 1}, {0.01, 0.01, 1}, {0, 0, 1}}},
+				{{{2, 2, 1}, {3, 2, 2}, {3, 3, 3}, {2.01, 3.01, 4}, {2, 2, 1}}},
+			},
+			want: MultiPolygonZ{{{{2, 2, 1}, {3, 2, 2}, {3, 3, 3}, {2, 3, 4}, {2, 2, 1}}}},
+		},
+	}
+	for i, test := range tests {
+		have := SnapToGrid(test.g, 0.1)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestPrecisionModelMakePrecise(t *testing.T) {
+	tests := []struct {
+		pm      PrecisionModel
+		p, want Point
+	}{
+		{pm: PrecisionModel{}, p: Point{0.123, 4.567}, want: Point{0.123, 4.567}},
+		{pm: PrecisionModel{Type: FixedPrecision, GridSize: 0.01}, p: Point{0.123, 4.567}, want: Point{0.12, 4.57}},
+		{pm: PrecisionModel{Type: FixedPrecision, GridSize: 5}, p: Point{12, -13}, want: Point{10, -15}},
+		{pm: PrecisionModel{Type: Float32Precision}, p: Point{0.1, 1}, want: Point{float64(float32(0.1)), 1}},
+	}
+	for i, test := range tests {
+		have := test.pm.MakePrecise(test.p)
+		if have != test.want {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestPrecisionModelOverlay(t *testing.T) {
+	a := Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	// b nearly touches a, and c nearly shares an edge with a.
+	b := Polygon{{{10.0000001, 0}, {20, 0}, {20, 10}, {10.0000001, 10}, {10.0000001, 0}}}
+	c := Polygon{{{9.9999999, 1e-8}, {20, 0}, {20, 10}, {9.9999999, 10}, {9.9999999, 1e-8}}}
+	fixed := PrecisionModel{Type: FixedPrecision, GridSize: 0.001}
+	float32Model := PrecisionModel{Type: Float32Precision}
+
+	tests := []struct {
+		result Polygonal
+		rings  int
+		area   float64
+	}{
+		{result: a.Union(b), rings: 2, area: 200 - 1e-6},
+		{result: fixed.Union(a, b), rings: 1, area: 200},
+		{result: float32Model.Union(a, b), rings: 1, area: 200},
+		{result: a.Intersection(c), rings: 1, area: 1e-6},
+		{result: fixed.Intersection(a, c), rings: 0, area: 0},
+		{result: fixed.Difference(a, c), rings: 1, area: 100},
+		{result: fixed.XOr(a, c), rings: 1, area: 200},
+	}
+	for i, test := range tests {
+		var rings int
+		for _, p := range test.result.Polygons() {
+			rings += len(p)
+		}
+		if rings != test.rings {
+			t.Errorf("%d: have %d rings, want %d", i, rings, test.rings)
+		}
+		if area := test.result.Area(); math.Abs(area-test.area) > 1e-9 {
+			t.Errorf("%d: have area %g, want %g", i, area, test.area)
+		}
+	}
+}
@@ -11,6 +11,10 @@ import (
 // A Transformer takes input coordinates and returns output coordinates and an error.
 type Transformer func(X, Y float64) (x, y float64, err error)
 
+// A Transformer3D takes input coordinates including height and returns
+// output coordinates and an error.
+type Transformer3D func(X, Y, Z float64) (x, y, z float64, err error)
+
 // A TransformerFunc creates forward and inverse Transformers from a projection.
 type TransformerFunc func(*SR) (forward, inverse Transformer, err error)
 
@@ -277,3 +277,39 @@ func TestWKTParse(t *testing.T) {
 		t.Errorf("have\n\t%#v\nwant\n\t%#v", sr, want)
 	}
 }
+
+func TestProj2Proj3D(t *testing.T) {
+	sweref99tm, err := Parse("+proj=utm +zone=33 +ellps=GRS80 +towgs84=0,0,0,0,0,0,0 +units=m +no_defs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt90, err := Parse("+lon_0=15.808277777799999 +lat_0=0.0 +k=1.0 +x_0=1500000.0 +y_0=0.0 +proj=tmerc +ellps=bessel +units=m +towgs84=414.1,41.3,603.1,-0.855,2.141,-7.023,0 +no_defs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans, err := sweref99tm.NewTransform3D(rt90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y, z, err := trans(319180, 6399862, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeTo(t, x, 1271137.927154, 0.000001, "x")
+	closeTo(t, y, 6404230.291456, 0.000001, "y")
+	if math.Abs(z-100) < 1 {
+		t.Errorf("height should have been shifted by the datum transformation, but is %g", z)
+	}
+
+	inverse, err := rt90.NewTransform3D(sweref99tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y, z, err = inverse(x, y, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeTo(t, x, 319180, 0.001, "x")
+	closeTo(t, y, 6399862, 0.001, "y")
+	closeTo(t, z, 100, 0.001, "z")
+}
@@ -16,6 +16,21 @@ const longlat = "longlat"
 // to the destination spatial reference. If source ~= dest, the returned
 // Transformer will be nil.
 func (source *SR) NewTransform(dest *SR) (Transformer, error) {
+	t, err := source.NewTransform3D(dest)
+	if t == nil || err != nil {
+		return nil, err
+	}
+	return func(x, y float64) (float64, float64, error) {
+		x, y, _, err := t(x, y, 0)
+		return x, y, err
+	}, nil
+}
+
+// NewTransform3D creates a function that transforms a point, including
+// its height, from sr to the destination spatial reference. Heights are
+// only changed by datum shifts and by axis orders that point down.
+// If source ~= dest, the returned Transformer3D will be nil.
+func (source *SR) NewTransform3D(dest *SR) (Transformer3D, error) {
 	if dest == nil {
 		return nil, fmt.Errorf("proj: destination is nil")
 	}
@@ -26,38 +41,41 @@ func (source *SR) NewTransform(dest *SR) (Transformer, error) {
 		return nil, nil
 	}
 
-	return func(x, y float64) (float64, float64, error) {
-		point := []float64{x, y}
+	return func(x, y, z float64) (float64, float64, float64, error) {
+		point := []float64{x, y, z}
+		source := source
 		// Workaround for datum shifts towgs84, if either source or destination projection is not wgs84
 		if checkNotWGS(source, dest) || checkNotWGS(dest, source) {
 			wgs84, err := Parse("WGS84")
 			if err != nil {
-				return math.NaN(), math.NaN(), err
+				return math.NaN(), math.NaN(), math.NaN(), err
 			}
-			t, err := source.NewTransform(wgs84)
+			t, err := source.NewTransform3D(wgs84)
 			if err != nil {
-				return math.NaN(), math.NaN(), err
+				return math.NaN(), math.NaN(), math.NaN(), err
 			}
-			point[0], point[1], err = t(point[0], point[1])
-			if err != nil {
-				return math.NaN(), math.NaN(), err
+			if t != nil {
+				point[0], point[1], point[2], err = t(point[0], point[1], point[2])
+				if err != nil {
+					return math.NaN(), math.NaN(), math.NaN(), err
+				}
 			}
 			source = wgs84
 		}
 		_, sourceInverse, err := source.Transformers()
 		if err != nil {
-			return math.NaN(), math.NaN(), err
+			return math.NaN(), math.NaN(), math.NaN(), err
 		}
 		destForward, _, err := dest.Transformers()
 		if err != nil {
-			return math.NaN(), math.NaN(), err
+			return math.NaN(), math.NaN(), math.NaN(), err
 		}
 
 		// DGR, 2010/11/12
 		if source.Axis != enu {
 			point, err = adjust_axis(source, false, point)
 			if err != nil {
-				return math.NaN(), math.NaN(), err
+				return math.NaN(), math.NaN(), math.NaN(), err
 			}
 		}
 		// Transform source points to long/lat, if they aren't already.
@@ -69,7 +87,7 @@ func (source *SR) NewTransform(dest *SR) (Transformer, error) {
 			point[1] *= source.ToMeter
 			point[0], point[1], err = sourceInverse(point[0], point[1]) // Convert Cartesian to longlat
 			if err != nil {
-				return math.NaN(), math.NaN(), err
+				return math.NaN(), math.NaN(), math.NaN(), err
 			}
 		}
 		// Adjust for the prime meridian if necessary
@@ -78,17 +96,10 @@ func (source *SR) NewTransform(dest *SR) (Transformer, error) {
 		}
 
 		// Convert datums if needed, and if possible.
-		z := 0.
-		if len(point) == 3 {
-			z = point[2]
-		}
-		point[0], point[1], z, err = datumTransform(source.datum, dest.datum,
-			point[0], point[1], z)
+		point[0], point[1], point[2], err = datumTransform(source.datum, dest.datum,
+			point[0], point[1], point[2])
 		if err != nil {
-			return math.NaN(), math.NaN(), err
-		}
-		if len(point) == 3 {
-			point[2] = z
+			return math.NaN(), math.NaN(), math.NaN(), err
 		}
 
 		// Adjust for the prime meridian if necessary
@@ -103,7 +114,7 @@ func (source *SR) NewTransform(dest *SR) (Transformer, error) {
 		} else { // else project
 			point[0], point[1], err = destForward(point[0], point[1])
 			if err != nil {
-				return math.NaN(), math.NaN(), err
+				return math.NaN(), math.NaN(), math.NaN(), err
 			}
 			point[0] /= dest.ToMeter
 			point[1] /= dest.ToMeter
@@ -113,10 +124,10 @@ func (source *SR) NewTransform(dest *SR) (Transformer, error) {
 		if dest.Axis != enu {
 			point, err = adjust_axis(dest, true, point)
 			if err != nil {
-				return math.NaN(), math.NaN(), err
+				return math.NaN(), math.NaN(), math.NaN(), err
 			}
 		}
 
-		return point[0], point[1], nil
+		return point[0], point[1], point[2], nil
 	}, nil
 }
@@ -0,0 +1,224 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// HausdorffDistance returns the discrete Hausdorff distance between a and b:
+// the greatest distance from a vertex of either geometry to the nearest point
+// on the other geometry. It also returns the two points that realize the
+// distance, where pa is on a and pb is on b. Polygons are treated as
+// their rings, so a point in the interior of a polygon is not at zero
+// distance from it. Z coordinates and measures are ignored.
+//
+// If densifyFrac is greater than zero, extra vertices are added to each
+// segment of the geometries so that the segment is split into pieces
+// no longer than densifyFrac times its original length, which makes
+// the result closer to the exact (continuous) Hausdorff distance.
+func HausdorffDistance(a, b Geom, densifyFrac float64) (dist float64, pa, pb Point) {
+	pathsA, pathsB := geomPaths(a), geomPaths(b)
+	dist = -1
+	// Distances from the vertices of a to b...
+	for _, path := range densifyPaths(pathsA, densifyFrac) {
+		for _, p := range path {
+			if q, d := nearestOnPaths(p, pathsB); d > dist {
+				dist, pa, pb = d, p, q
+			}
+		}
+	}
+	// ...and from the vertices of b to a.
+	for _, path := range densifyPaths(pathsB, densifyFrac) {
+		for _, p := range path {
+			if q, d := nearestOnPaths(p, pathsA); d > dist {
+				dist, pa, pb = d, q, p
+			}
+		}
+	}
+	if dist < 0 {
+		return math.NaN(), nanPoint, nanPoint
+	}
+	return dist, pa, pb
+}
+
+// FrechetDistance returns the discrete Fréchet distance between a and b,
+// which measures the similarity of two curves while taking into account
+// the order of their vertices (as given by the Points method). It also
+// returns the pair of vertices that realizes the distance, where pa is a
+// vertex of a and pb is a vertex of b. Z coordinates and measures are
+// ignored.
+//
+// If densifyFrac is greater than zero, extra vertices are added to each
+// segment of the geometries so that the segment is split into pieces
+// no longer than densifyFrac times its original length.
+//
+// The algorithm is from T. Eiter and H. Mannila, Computing discrete
+// Fréchet distance. Technical Report CD-TR 94/64, TU Vienna (1994).
+func FrechetDistance(a, b Geom, densifyFrac float64) (dist float64, pa, pb Point) {
+	va := pathVertices(densifyPaths(orderedPaths(a), densifyFrac))
+	vb := pathVertices(densifyPaths(orderedPaths(b), densifyFrac))
+	n, m := len(va), len(vb)
+	if n == 0 || m == 0 {
+		return math.NaN(), nanPoint, nanPoint
+	}
+
+	// ca[i][j] is the Fréchet distance between va[:i+1] and vb[:j+1].
+	ca := make([][]float64, n)
+	for i := range ca {
+		ca[i] = make([]float64, m)
+		for j := range ca[i] {
+			dij := d(va[i], vb[j])
+			switch {
+			case i == 0 && j == 0:
+				ca[i][j] = dij
+			case i == 0:
+				ca[i][j] = math.Max(ca[i][j-1], dij)
+			case j == 0:
+				ca[i][j] = math.Max(ca[i-1][j], dij)
+			default:
+				prev := math.Min(ca[i-1][j], math.Min(ca[i-1][j-1], ca[i][j-1]))
+				ca[i][j] = math.Max(prev, dij)
+			}
+		}
+	}
+	dist = ca[n-1][m-1]
+
+	// Follow the optimal coupling backwards to find the vertices that are
+	// separated by the Fréchet distance.
+	i, j := n-1, m-1
+	for d(va[i], vb[j]) != dist {
+		switch {
+		case i == 0:
+			j--
+		case j == 0:
+			i--
+		case ca[i-1][j-1] <= ca[i-1][j] && ca[i-1][j-1] <= ca[i][j-1]:
+			i, j = i-1, j-1
+		case ca[i-1][j] <= ca[i][j-1]:
+			i--
+		default:
+			j--
+		}
+	}
+	return dist, va[i], vb[j]
+}
+
+// geomPaths returns the vertices and edges of g as a set of paths. Points
+// are represented as paths with a single vertex, polygon rings are
+// closed, and Z coordinates and measures are ignored.
+func geomPaths(g Geom) []Path {
+	switch g := g.(type) {
+	case Point:
+		return []Path{{g}}
+	case MultiPoint:
+		o := make([]Path, len(g))
+		for i, p := range g {
+			o[i] = Path{p}
+		}
+		return o
+	case LineString:
+		return []Path{Path(g)}
+	case MultiLineString:
+		o := make([]Path, len(g))
+		for i, l := range g {
+			o[i] = Path(l)
+		}
+		return o
+	case Polygonal:
+		var o []Path
+		for _, p := range g.Polygons() {
+			for _, r := range p {
+				if len(r) > 1 && !r[0].Equals(r[len(r)-1]) {
+					r = append(append(Path{}, r...), r[0])
+				}
+				o = append(o, r)
+			}
+		}
+		return o
+	case Linear:
+		return geomPaths(linearLines(g))
+	case GeomZ:
+		return geomPaths(g.Force2D())
+	case LineStringM:
+		return geomPaths(g.Force2D())
+	case MultiLineStringM:
+		return geomPaths(g.Force2D())
+	case GeometryCollection:
+		var o []Path
+		for _, gg := range g {
+			o = append(o, geomPaths(gg)...)
+		}
+		return o
+	default:
+		panic(fmt.Errorf("geom: unsupported type %T", g))
+	}
+}
+
+// orderedPaths returns the paths of g, retaining the vertex order of the
+// Points iterator.
+func orderedPaths(g Geom) []Path {
+	switch g.(type) {
+	case Point, MultiPoint:
+		next := g.Points()
+		o := make(Path, g.Len())
+		for i := range o {
+			o[i] = next()
+		}
+		return []Path{o}
+	default:
+		return geomPaths(g)
+	}
+}
+
+// pathVertices returns all of the vertices in paths.
+func pathVertices(paths []Path) []Point {
+	var o []Point
+	for _, p := range paths {
+		o = append(o, p...)
+	}
+	return o
+}
+
+// densifyPaths splits each segment in paths into pieces no longer than
+// frac times the length of the segment. If frac <= 0, paths is returned
+// unchanged.
+func densifyPaths(paths []Path, frac float64) []Path {
+	if frac <= 0 {
+		return paths
+	}
+	n := int(math.Ceil(1 / frac))
+	o := make([]Path, len(paths))
+	for i, path := range paths {
+		for j, p := range path {
+			if j > 0 {
+				for k := 1; k < n; k++ {
+					o[i] = append(o[i], interpolateSegment(path[j-1], p, float64(k)/float64(n)))
+				}
+			}
+			o[i] = append(o[i], p)
+		}
+	}
+	return o
+}
+
+// nearestOnPaths returns the point on paths that is nearest to p, and its
+// distance from p.
+func nearestOnPaths(p Point, paths []Path) (Point, float64) {
+	nearest := nanPoint
+	dist := math.Inf(1)
+	for _, path := range paths {
+		if len(path) == 1 {
+			if dd := d(p, path[0]); dd < dist {
+				nearest, dist = path[0], dd
+			}
+			continue
+		}
+		for i := 0; i < len(path)-1; i++ {
+			q := interpolateSegment(path[i], path[i+1], projectSegment(p, path[i], path[i+1]))
+			if dd := d(p, q); dd < dist {
+				nearest, dist = q, dd
+			}
+		}
+	}
+	return nearest, dist
+}
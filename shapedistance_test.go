@@ -0,0 +1,105 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHausdorffDistance(t *testing.T) {
+	tests := []struct {
+		a, b        Geom
+		densifyFrac float64
+		dist        float64
+		pa, pb      Point
+	}{
+		{
+			a:    LineString{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 10, Y: 100}, {X: 10, Y: 100}},
+			b:    LineString{{X: 0, Y: 100}, {X: 0, Y: 10}, {X: 80, Y: 10}},
+			dist: 22.360679774997898,
+			pa:   Point{X: 100, Y: 0},
+			pb:   Point{X: 80, Y: 10},
+		},
+		{
+			// Densification finds the maximum in the middle of a segment.
+			a:           LineString{{X: 130, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 150}},
+			b:           LineString{{X: 10, Y: 10}, {X: 10, Y: 150}, {X: 130, Y: 10}},
+			densifyFrac: 0.5,
+			dist:        70,
+			pa:          Point{X: 0, Y: 80},
+			pb:          Point{X: 70, Y: 80},
+		},
+		{
+			a:    MultiPoint{{X: 0, Y: 0}, {X: 1, Y: 0}},
+			b:    Polygon{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}},
+			dist: 5,
+			pa:   Point{X: 1, Y: 0},
+			pb:   Point{X: 4, Y: 4},
+		},
+		{
+			// Z coordinates and measures are ignored.
+			a:    LineStringZ{{X: 0, Y: 0, Z: 50}, {X: 100, Y: 0, Z: -50}, {X: 10, Y: 100, Z: 0}},
+			b:    MultiLineStringM{{{X: 0, Y: 100, M: 0}, {X: 0, Y: 10, M: 90}, {X: 80, Y: 10, M: 170}}},
+			dist: 22.360679774997898,
+			pa:   Point{X: 100, Y: 0},
+			pb:   Point{X: 80, Y: 10},
+		},
+		{
+			a:    PolygonZ{{{X: 0, Y: 0, Z: 1}, {X: 4, Y: 0, Z: 1}, {X: 4, Y: 4, Z: 1}, {X: 0, Y: 4, Z: 1}}},
+			b:    MultiPointZ{{X: 0, Y: 0, Z: 9}, {X: 1, Y: 0, Z: 9}},
+			dist: 5,
+			pa:   Point{X: 4, Y: 4},
+			pb:   Point{X: 1, Y: 0},
+		},
+	}
+	for i, test := range tests {
+		dist, pa, pb := HausdorffDistance(test.a, test.b, test.densifyFrac)
+		if math.Abs(dist-test.dist) > 1.e-9 {
+			t.Errorf("%d: have distance %g, want %g", i, dist, test.dist)
+		}
+		if !pointSimilar(pa, test.pa, 1.e-9) || !pointSimilar(pb, test.pb, 1.e-9) {
+			t.Errorf("%d: have points %v, %v; want %v, %v", i, pa, pb, test.pa, test.pb)
+		}
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	tests := []struct {
+		a, b   Geom
+		dist   float64
+		pa, pb Point
+	}{
+		{
+			a:    LineString{{X: 0, Y: 0}, {X: 100, Y: 0}},
+			b:    LineString{{X: 0, Y: 0}, {X: 50, Y: 50}, {X: 100, Y: 0}},
+			dist: 70.71067811865476,
+			pa:   Point{X: 0, Y: 0},
+			pb:   Point{X: 50, Y: 50},
+		},
+		{
+			// The Hausdorff distance between these lines is zero, but they
+			// run in opposite directions.
+			a:    LineString{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}},
+			b:    LineString{{X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}},
+			dist: 2,
+			pa:   Point{X: 2, Y: 0},
+			pb:   Point{X: 0, Y: 0},
+		},
+		{
+			// Z coordinates and measures are ignored.
+			a:    LineStringZ{{X: 0, Y: 0, Z: 3}, {X: 100, Y: 0, Z: 3}},
+			b:    LineStringM{{X: 0, Y: 0, M: 0}, {X: 50, Y: 50, M: 1}, {X: 100, Y: 0, M: 2}},
+			dist: 70.71067811865476,
+			pa:   Point{X: 0, Y: 0},
+			pb:   Point{X: 50, Y: 50},
+		},
+	}
+	for i, test := range tests {
+		dist, pa, pb := FrechetDistance(test.a, test.b, 0)
+		if math.Abs(dist-test.dist) > 1.e-9 {
+			t.Errorf("%d: have distance %g, want %g", i, dist, test.dist)
+		}
+		if !pointSimilar(pa, test.pa, 1.e-9) || !pointSimilar(pb, test.pb, 1.e-9) {
+			t.Errorf("%d: have points %v, %v; want %v, %v", i, pa, pb, test.pa, test.pb)
+		}
+	}
+}
@@ -71,10 +71,20 @@ func (ml MultiLineString) Simplify(tolerance float64) Geom {
 
 func simplifyCurve(curve Path,
 	otherCurves []Path, tol float64) []Point {
+	return simplifyCurveChecked(curve, otherCurves, tol, segMakesNotSimple)
+}
+
+// simplifyCurveChecked is like simplifyCurve, but uses crosses to check
+// whether a shortcut from one point of curve to another would intersect
+// paths.
+func simplifyCurveChecked(curve Path, otherCurves []Path, tol float64,
+	crosses func(a, b Point, paths []Path) bool) []Point {
 	out := make([]Point, 0, len(curve))
 
 	if len(curve) == 0 {
 		return nil
+	} else if len(curve) < 3 {
+		return append(out, curve...)
 	}
 
 	i := 0
@@ -91,9 +101,9 @@ func simplifyCurve(curve Path,
 						// Make sure this simplification doesn't cause any self
 						// intersections.
 						if j > i+2 &&
-							(segMakesNotSimple(curve[i], curve[j-1], []Path{out[0:i]}) ||
-								segMakesNotSimple(curve[i], curve[j-1], []Path{curve[j:]}) ||
-								segMakesNotSimple(curve[i], curve[j-1], otherCurves)) {
+							(crosses(curve[i], curve[j-1], []Path{out[0:i]}) ||
+								crosses(curve[i], curve[j-1], []Path{curve[j:]}) ||
+								crosses(curve[i], curve[j-1], otherCurves)) {
 							j--
 						} else {
 							i = j - 1
@@ -108,7 +118,23 @@ func simplifyCurve(curve Path,
 				}
 			}
 			if j == len(curve)-1 {
-				// Add last point regardless of distance.
+				// Add last point regardless of distance, unless the shortcut
+				// to it would cause a self intersection, in which case keep
+				// the furthest point that can be reached without one.
+				k := j
+				for k > i+1 &&
+					(crosses(curve[i], curve[k], []Path{out[0:i]}) ||
+						crosses(curve[i], curve[k], []Path{curve[k+1:]}) ||
+						crosses(curve[i], curve[k], otherCurves)) {
+					k--
+				}
+				if k < j-1 {
+					i = k
+					break
+				}
+				if k == j-1 {
+					out = append(out, curve[k])
+				}
 				out = append(out, curve[j])
 				breakTime = true
 			}
@@ -146,14 +172,8 @@ func pointOnSegment(p, l1, l2 Point) bool {
 		(p.Y < l1.Y && p.Y < l2.Y) || (p.Y > l1.Y && p.Y > l2.Y) {
 		return false
 	}
-	d1 := pointSubtract(l1, p)
-	d2 := pointSubtract(l2, l1)
-
-	// If the two slopes are the same, then the point is on the line
-	if (d1.X == 0 && d2.X == 0) || d1.Y/d1.X == d2.Y/d2.X {
-		return true
-	}
-	return false
+	// If the points are collinear, then the point is on the line.
+	return orient(l1, l2, p) == 0
 }
 
 // dist_Point_to_Segment(): get the distance of a point to a segment
@@ -0,0 +1,319 @@
+package geom
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// SimplifyAlgorithm specifies the algorithm used to simplify a geometry.
+type SimplifyAlgorithm int
+
+const (
+	// Pallero is the algorithm used by the Simplify methods:
+	// J. L. G. Pallero, Robust line simplification on the plane.
+	// Comput. Geosci. 61, 152–159 (2013). It is the slowest of the
+	// algorithms, but it always prevents self intersections.
+	Pallero SimplifyAlgorithm = iota
+
+	// DouglasPeucker is the classic algorithm from D. H. Douglas and
+	// T. K. Peucker, Algorithms for the reduction of the number of points
+	// required to represent a digitized line or its caricature.
+	// Cartographica 10(2), 112–122 (1973).
+	DouglasPeucker
+
+	// VisvalingamWhyatt removes the points with the smallest effective
+	// area first, following M. Visvalingam and J. D. Whyatt, Line
+	// generalisation by repeated elimination of points. Cartogr. J. 30(1),
+	// 46–51 (1993). It tends to give smoother results than DouglasPeucker.
+	VisvalingamWhyatt
+)
+
+// SimplifyOptions specifies how a geometry should be simplified.
+type SimplifyOptions struct {
+	// Algorithm is the simplification algorithm to use.
+	Algorithm SimplifyAlgorithm
+
+	// Tolerance controls how many points are removed. For Pallero and
+	// DouglasPeucker, it is the maximum distance between a removed point
+	// and the simplified line. For VisvalingamWhyatt, points whose
+	// effective area is less than Tolerance² are removed.
+	Tolerance float64
+
+	// PreventSelfIntersection specifies that points should be kept where
+	// necessary to ensure that the simplified shape is not self
+	// intersecting (as long as the input shape is not self intersecting).
+	// The Pallero algorithm always prevents self intersections.
+	PreventSelfIntersection bool
+}
+
+// Simplify returns a simplified version of g. LineStrings, MultiLineStrings,
+// Polygons, MultiPolygons, and the members of GeometryCollections are
+// simplified, and so are other Polygonal and Linear types, which are
+// returned as MultiPolygons and MultiLineStrings; other types are returned
+// unchanged. Polygon rings are never
+// reduced to fewer than four points.
+func (o SimplifyOptions) Simplify(g Geom) Geom {
+	switch g := g.(type) {
+	case Point, MultiPoint, *Bounds:
+		return g
+	case LineString:
+		return LineString(o.simplifyCurve(Path(g), nil, 2))
+	case MultiLineString:
+		out := make(MultiLineString, len(g))
+		for i, l := range g {
+			out[i] = LineString(o.simplifyCurve(Path(l), nil, 2))
+		}
+		return out
+	case Polygon:
+		return o.simplifyPolygon(g)
+	case MultiPolygon:
+		out := make(MultiPolygon, len(g))
+		for i, p := range g {
+			out[i] = o.simplifyPolygon(p)
+		}
+		return out
+	case GeometryCollection:
+		out := make(GeometryCollection, len(g))
+		for i, gg := range g {
+			out[i] = o.Simplify(gg)
+		}
+		return out
+	case Polygonal:
+		return o.Simplify(MultiPolygon(g.Polygons()))
+	case Linear:
+		return o.Simplify(linearLines(g))
+	default:
+		panic(fmt.Errorf("geom: unsupported type %T", g))
+	}
+}
+
+// simplifyPolygon simplifies each of the rings in p, making sure that
+// they do not intersect the other rings in their current state.
+func (o SimplifyOptions) simplifyPolygon(p Polygon) Polygon {
+	out := make(Polygon, len(p))
+	copy(out, p)
+	for i, r := range p {
+		others := make([]Path, 0, len(p)-1)
+		others = append(others, out[:i]...)
+		others = append(others, out[i+1:]...)
+		out[i] = o.simplifyCurve(r, others, 4)
+	}
+	return out
+}
+
+// simplifyCurve simplifies curve, keeping at least minPoints points
+// (or all of the points if there are fewer).
+func (o SimplifyOptions) simplifyCurve(curve Path, otherCurves []Path, minPoints int) Path {
+	if len(curve) <= minPoints {
+		return append(Path{}, curve...)
+	}
+	var keep []bool
+	switch o.Algorithm {
+	case Pallero:
+		return simplifyCurve(curve, otherCurves, o.Tolerance)
+	case DouglasPeucker:
+		keep = douglasPeucker(curve, o.Tolerance)
+	case VisvalingamWhyatt:
+		keep = visvalingamWhyatt(curve, o.Tolerance*o.Tolerance, minPoints)
+	default:
+		panic(fmt.Errorf("geom: invalid simplification algorithm %d", o.Algorithm))
+	}
+	for keptPoints(keep) < minPoints && restorePoint(curve, keep, 0, len(curve)-1) {
+	}
+	if o.PreventSelfIntersection {
+		makeSimple(curve, keep, otherCurves)
+	}
+	out := make(Path, 0, keptPoints(keep))
+	for i, k := range keep {
+		if k {
+			out = append(out, curve[i])
+		}
+	}
+	return out
+}
+
+// douglasPeucker returns which points of curve should be kept according to
+// the Douglas-Peucker algorithm.
+func douglasPeucker(curve Path, tol float64) []bool {
+	keep := make([]bool, len(curve))
+	keep[0], keep[len(curve)-1] = true, true
+	stack := [][2]int{{0, len(curve) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		k, dist := farthestPoint(curve, s[0], s[1])
+		if k >= 0 && dist > tol {
+			keep[k] = true
+			stack = append(stack, [2]int{s[0], k}, [2]int{k, s[1]})
+		}
+	}
+	return keep
+}
+
+// visvalingamWhyatt returns which points of curve should be kept according
+// to the Visvalingam-Whyatt algorithm, keeping at least minPoints points.
+func visvalingamWhyatt(curve Path, minArea float64, minPoints int) []bool {
+	n := len(curve)
+	keep := make([]bool, n)
+	prev := make([]int, n)
+	next := make([]int, n)
+	for i := range curve {
+		keep[i] = true
+		prev[i], next[i] = i-1, i+1
+	}
+	h := make(vwHeap, 0, n-2)
+	items := make([]*vwItem, n)
+	for i := 1; i < n-1; i++ {
+		items[i] = &vwItem{i: i, area: triangleArea(curve[i-1], curve[i], curve[i+1]), pos: len(h)}
+		h = append(h, items[i])
+	}
+	heap.Init(&h)
+	for count := n; h.Len() > 0 && count > minPoints; count-- {
+		it := heap.Pop(&h).(*vwItem)
+		if it.area >= minArea {
+			break
+		}
+		keep[it.i] = false
+		p, nx := prev[it.i], next[it.i]
+		next[p], prev[nx] = nx, p
+		// The effective area of a point is never allowed to be smaller
+		// than that of a point that has already been removed, so that
+		// points are removed in a consistent order.
+		for _, j := range []int{p, nx} {
+			if j == 0 || j == n-1 {
+				continue
+			}
+			items[j].area = math.Max(triangleArea(curve[prev[j]], curve[j], curve[next[j]]), it.area)
+			heap.Fix(&h, items[j].pos)
+		}
+	}
+	return keep
+}
+
+// triangleArea returns the area of the triangle with vertices a, b, and c.
+func triangleArea(a, b, c Point) float64 {
+	return math.Abs((b.X-a.X)*(c.Y-a.Y)-(c.X-a.X)*(b.Y-a.Y)) / 2
+}
+
+type vwItem struct {
+	i    int
+	area float64
+	pos  int
+}
+
+// vwHeap is a min-heap of points ordered by their effective area.
+type vwHeap []*vwItem
+
+func (h vwHeap) Len() int           { return len(h) }
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos, h[j].pos = i, j
+}
+func (h *vwHeap) Push(x interface{}) {
+	it := x.(*vwItem)
+	it.pos = len(*h)
+	*h = append(*h, it)
+}
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// farthestPoint returns the index of the point of curve between indices
+// start and end (exclusive) that is farthest from the segment between
+// curve[start] and curve[end], and its distance. It returns -1 if there are
+// no points in between.
+func farthestPoint(curve Path, start, end int) (int, float64) {
+	k, dist := -1, -1.
+	for i := start + 1; i < end; i++ {
+		if dd := distPointToSegment(curve[i], curve[start], curve[end]); dd > dist {
+			k, dist = i, dd
+		}
+	}
+	return k, dist
+}
+
+// keptPoints returns the number of true values in keep.
+func keptPoints(keep []bool) int {
+	n := 0
+	for _, k := range keep {
+		if k {
+			n++
+		}
+	}
+	return n
+}
+
+// restorePoint restores the removed point between indices start and end of
+// curve that is farthest from the simplified curve. It returns false if
+// there are no removed points between start and end.
+func restorePoint(curve Path, keep []bool, start, end int) bool {
+	best, bestDist := -1, -1.
+	a := start
+	for b := start + 1; b <= end; b++ {
+		if !keep[b] {
+			continue
+		}
+		if k, dist := farthestPoint(curve, a, b); k >= 0 && dist > bestDist {
+			best, bestDist = k, dist
+		}
+		a = b
+	}
+	if best < 0 {
+		return false
+	}
+	keep[best] = true
+	return true
+}
+
+// makeSimple restores removed points of curve until the simplified curve
+// intersects neither itself nor otherCurves.
+func makeSimple(curve Path, keep []bool, otherCurves []Path) {
+	for {
+		simplified := make(Path, 0, len(curve))
+		idx := make([]int, 0, len(curve))
+		for i, k := range keep {
+			if k {
+				simplified = append(simplified, curve[i])
+				idx = append(idx, i)
+			}
+		}
+		changed := false
+		for j := 0; j < len(idx)-1; j++ {
+			a, b := idx[j], idx[j+1]
+			if b == a+1 {
+				continue // This segment is unchanged.
+			}
+			if segmentCrossesPaths(curve[a], curve[b], []Path{simplified}) ||
+				segmentCrossesPaths(curve[a], curve[b], otherCurves) {
+				restorePoint(curve, keep, a, b)
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// segmentCrossesPaths returns whether the segment from a to b intersects
+// any of the segments of paths, ignoring the segments that have a or b as
+// an endpoint.
+func segmentCrossesPaths(a, b Point, paths []Path) bool {
+	seg := segment{a, b}
+	for _, p := range paths {
+		for i := 0; i < len(p)-1; i++ {
+			if p[i] == a || p[i] == b || p[i+1] == a || p[i+1] == b {
+				continue
+			}
+			if n, _, _ := findIntersection(seg, segment{p[i], p[i+1]}); n > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
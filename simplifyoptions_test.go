@@ -0,0 +1,125 @@
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimplifyOptions(t *testing.T) {
+	line := LineString{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {4, 6}, {5, 7},
+		{6, 8.1}, {7, 9}, {8, 9}, {9, 9}}
+	// The shortcut across the bottom of the outer ring would cross the hole
+	// unless the vertex at (5, -1) is kept.
+	poly := Polygon{
+		{{0, 0}, {4, 0}, {5, -1}, {6, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{4.8, -0.5}, {5.2, -0.5}, {5.2, 0.5}, {4.8, 0.5}, {4.8, -0.5}},
+	}
+	tests := []struct {
+		opts SimplifyOptions
+		g    Geom
+		want Geom
+	}{
+		{
+			opts: SimplifyOptions{Algorithm: DouglasPeucker, Tolerance: 1},
+			g:    line,
+			want: LineString{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: VisvalingamWhyatt, Tolerance: 1},
+			g:    line,
+			want: LineString{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: DouglasPeucker, Tolerance: 100},
+			g:    LineString{{0, 0}, {1, 1}},
+			want: LineString{{0, 0}, {1, 1}},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: Pallero, Tolerance: 100},
+			g:    MultiLineString{{{0, 0}, {1, 1}}, {{0, 0}}},
+			want: MultiLineString{{{0, 0}, {1, 1}}, {{0, 0}}},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: DouglasPeucker, Tolerance: 2},
+			g:    poly,
+			want: Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.8, -0.5}, {5.2, -0.5}, {5.2, 0.5}, {4.8, -0.5}},
+			},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: DouglasPeucker, Tolerance: 2, PreventSelfIntersection: true},
+			g:    poly,
+			want: Polygon{
+				{{0, 0}, {5, -1}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.8, -0.5}, {5.2, -0.5}, {5.2, 0.5}, {4.8, -0.5}},
+			},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: VisvalingamWhyatt, Tolerance: 2},
+			g:    poly,
+			want: Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.8, -0.5}, {5.2, 0.5}, {4.8, 0.5}, {4.8, -0.5}},
+			},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: VisvalingamWhyatt, Tolerance: 2, PreventSelfIntersection: true},
+			g:    MultiPolygon{poly},
+			want: MultiPolygon{{
+				{{0, 0}, {5, -1}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.8, -0.5}, {5.2, 0.5}, {4.8, 0.5}, {4.8, -0.5}},
+			}},
+		},
+		{
+			opts: SimplifyOptions{Algorithm: VisvalingamWhyatt, Tolerance: 2},
+			g:    GeometryCollection{Point{1, 2}, line},
+			want: GeometryCollection{Point{1, 2}, LineString{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}}},
+		},
+	}
+	for i, test := range tests {
+		have := test.opts.Simplify(test.g)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestSimplifyOptionsPallero(t *testing.T) {
+	l := LineString{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {4, 6}, {5, 7}}
+	have := SimplifyOptions{Tolerance: 0.5}.Simplify(l)
+	want := l.Simplify(0.5)
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+}
+
+// lineSimple returns whether l does not intersect itself, other than where
+// adjacent segments meet.
+func lineSimple(l LineString) bool {
+	for i := 0; i < len(l)-1; i++ {
+		for j := i + 1; j < len(l)-1; j++ {
+			n, _, _ := findIntersection(segment{l[i], l[i+1]}, segment{l[j], l[j+1]})
+			if j == i+1 && n > 1 || j > i+1 && n > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestSimplifyOptionsPreventSelfIntersection(t *testing.T) {
+	// Without the vertex at (5, -1), the bottom of the line would cross the
+	// spike that reaches down to (5, -0.5).
+	l := LineString{{0, 0}, {5, -1}, {10, 0}, {10, 3}, {6, 3}, {5, -0.5}, {4, 3}, {0, 3}, {-2, 3}}
+	for _, alg := range []SimplifyAlgorithm{DouglasPeucker, VisvalingamWhyatt} {
+		opts := SimplifyOptions{Algorithm: alg, Tolerance: 1.2, PreventSelfIntersection: true}
+		s := opts.Simplify(l).(LineString)
+		if !lineSimple(s) {
+			t.Errorf("%d: %v is not simple", alg, s)
+		}
+		if len(s) >= len(l) {
+			t.Errorf("%d: %v was not simplified", alg, s)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package geom
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// Skeleton returns an approximation of the medial axis of p: the set of
+// lines that run through the middle of p. It is calculated as the chordal
+// axis of a Delaunay triangulation of the boundary of p, after the
+// boundary has been densified so that no segment is longer than interval.
+// Smaller values of interval give more accurate results, and interval
+// should be much smaller than the width of p. The lines are merged so
+// that they only meet at the junctions where branches of the skeleton
+// split.
+func (p Polygon) Skeleton(interval float64) MultiLineString {
+	var o MultiLineString
+	for _, e := range p.skeletonEdges(interval) {
+		o = append(o, LineString{e.start, e.end})
+	}
+	return o.LineMerge()
+}
+
+// Centerline returns the longest path through the skeleton of p (see
+// Skeleton), which runs from one end of p to the other. This is useful for
+// finding the centerline of an elongated shape such as a river or road,
+// while ignoring the short branches of the skeleton that lead into the
+// corners of p. It returns nil if p is too small compared to interval for
+// the skeleton to be calculated.
+func (p Polygon) Centerline(interval float64) LineString {
+	edges := p.skeletonEdges(interval)
+	if len(edges) == 0 {
+		return nil
+	}
+	ids := make(map[Point]int)
+	var nodes []Point
+	id := func(pt Point) int {
+		if i, ok := ids[pt]; ok {
+			return i
+		}
+		ids[pt] = len(nodes)
+		nodes = append(nodes, pt)
+		return len(nodes) - 1
+	}
+	type link struct {
+		to     int
+		length float64
+	}
+	var graph [][]link
+	for _, e := range edges {
+		a, b := id(e.start), id(e.end)
+		for len(graph) < len(nodes) {
+			graph = append(graph, nil)
+		}
+		length := d(e.start, e.end)
+		graph[a] = append(graph[a], link{to: b, length: length})
+		graph[b] = append(graph[b], link{to: a, length: length})
+	}
+
+	// Find the two nodes that are farthest apart.
+	farthest := func(from int) (int, []int) {
+		dist := make([]float64, len(nodes))
+		prev := make([]int, len(nodes))
+		for i := range dist {
+			dist[i] = math.Inf(1)
+			prev[i] = -1
+		}
+		dist[from] = 0
+		q := &distQueue{{node: from}}
+		for q.Len() > 0 {
+			it := heap.Pop(q).(distItem)
+			if it.dist > dist[it.node] {
+				continue
+			}
+			for _, l := range graph[it.node] {
+				if nd := it.dist + l.length; nd < dist[l.to] {
+					dist[l.to] = nd
+					prev[l.to] = it.node
+					heap.Push(q, distItem{node: l.to, dist: nd})
+				}
+			}
+		}
+		far := from
+		for i, dd := range dist {
+			if !math.IsInf(dd, 1) && dd > dist[far] {
+				far = i
+			}
+		}
+		return far, prev
+	}
+	start, _ := farthest(0)
+	end, prev := farthest(start)
+	var o LineString
+	for n := end; n >= 0; n = prev[n] {
+		o = append(o, nodes[n])
+	}
+	return o
+}
+
+// skeletonEdges returns the segments that make up the chordal axis of p.
+func (p Polygon) skeletonEdges(interval float64) []segment {
+	if !(interval > 0) {
+		panic(fmt.Errorf("geom: invalid interval %g", interval))
+	}
+	var pts []Point
+	seen := make(map[Point]struct{})
+	for _, r := range densifyPolygon(p, interval) {
+		for _, pt := range r {
+			if _, ok := seen[pt]; !ok {
+				seen[pt] = struct{}{}
+				pts = append(pts, pt)
+			}
+		}
+	}
+	tris := delaunay(pts)
+
+	// Find the triangles that are inside p, and the edges that they share.
+	type edge [2]int
+	key := func(a, b int) edge {
+		if a > b {
+			a, b = b, a
+		}
+		return edge{a, b}
+	}
+	var inside [][3]int
+	count := make(map[edge]int)
+	for _, t := range tris {
+		c := Point{
+			X: (pts[t[0]].X + pts[t[1]].X + pts[t[2]].X) / 3,
+			Y: (pts[t[0]].Y + pts[t[1]].Y + pts[t[2]].Y) / 3,
+		}
+		if pointInPolygonal(c, p) != Inside {
+			continue
+		}
+		inside = append(inside, t)
+		for k := 0; k < 3; k++ {
+			count[key(t[k], t[(k+1)%3])]++
+		}
+	}
+	midpoint := func(e edge) Point {
+		return Point{X: (pts[e[0]].X + pts[e[1]].X) / 2, Y: (pts[e[0]].Y + pts[e[1]].Y) / 2}
+	}
+
+	// Connect the midpoints of the edges that are shared between triangles.
+	var o []segment
+	for _, t := range inside {
+		var mids []Point
+		for k := 0; k < 3; k++ {
+			if e := key(t[k], t[(k+1)%3]); count[e] == 2 {
+				mids = append(mids, midpoint(e))
+			}
+		}
+		switch len(mids) {
+		case 2:
+			o = append(o, segment{mids[0], mids[1]})
+		case 3:
+			// At a junction, connect the edges to the triangle's centroid.
+			c := Point{
+				X: (pts[t[0]].X + pts[t[1]].X + pts[t[2]].X) / 3,
+				Y: (pts[t[0]].Y + pts[t[1]].Y + pts[t[2]].Y) / 3,
+			}
+			for _, m := range mids {
+				o = append(o, segment{m, c})
+			}
+		}
+	}
+	return o
+}
+
+type distItem struct {
+	node int
+	dist float64
+}
+
+// distQueue is a priority queue of graph nodes ordered by distance.
+type distQueue []distItem
+
+func (q distQueue) Len() int            { return len(q) }
+func (q distQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q distQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distQueue) Push(x interface{}) { *q = append(*q, x.(distItem)) }
+func (q *distQueue) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
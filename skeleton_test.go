@@ -0,0 +1,92 @@
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ctessum/geom/internal/robust"
+)
+
+func TestDelaunay(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	pts := make([]Point, 200)
+	for i := range pts {
+		pts[i] = Point{rnd.Float64(), rnd.Float64()}
+	}
+	// Include some cocircular points.
+	pts = append(pts, Point{2, 2}, Point{3, 2}, Point{3, 3}, Point{2, 3}, Point{2.5, 2.5})
+	tris := delaunay(pts)
+	var area float64
+	for _, tr := range tris {
+		a, b, c := pts[tr[0]], pts[tr[1]], pts[tr[2]]
+		if orient(a, b, c) <= 0 {
+			t.Fatalf("triangle %v is not counterclockwise", tr)
+		}
+		area += orient(a, b, c) / 2
+		for i, p := range pts {
+			if i != tr[0] && i != tr[1] && i != tr[2] &&
+				robust.InCircle(a.X, a.Y, b.X, b.Y, c.X, c.Y, p.X, p.Y) > 0 {
+				t.Fatalf("point %v is inside the circumcircle of %v", p, tr)
+			}
+		}
+	}
+	if len(tris) == 0 || area <= 0 {
+		t.Errorf("have %d triangles with area %g", len(tris), area)
+	}
+}
+
+func TestCenterline(t *testing.T) {
+	tests := []struct {
+		p      Polygon
+		length float64
+	}{
+		{
+			p:      Polygon{{{0, 0}, {20, 0}, {20, 2}, {0, 2}, {0, 0}}},
+			length: 20,
+		},
+		{
+			p:      Polygon{{{0, 0}, {20, 0}, {20, 20}, {18, 20}, {18, 2}, {0, 2}, {0, 0}}},
+			length: 38,
+		},
+	}
+	for i, test := range tests {
+		c := test.p.Centerline(0.5)
+		if l := c.Length(); math.Abs(l-test.length) > 1 {
+			t.Errorf("%d: have length %g, want about %g", i, l, test.length)
+		}
+		for _, pt := range c {
+			if pt.Within(test.p) != Inside {
+				t.Errorf("%d: %v is not inside the polygon", i, pt)
+			}
+		}
+		// The middle of the centerline should be far from the edges.
+		mid := c.Interpolate(c.Length() / 2)
+		if _, dist := nearestOnPaths(mid, test.p); math.Abs(dist-1) > 1e-9 {
+			t.Errorf("%d: midpoint %v is %g from the edge, want 1", i, mid, dist)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	// A plus-shaped polygon has a skeleton that runs along each arm.
+	p := Polygon{{{4, 0}, {6, 0}, {6, 4}, {10, 4}, {10, 6}, {6, 6}, {6, 10},
+		{4, 10}, {4, 6}, {0, 6}, {0, 4}, {4, 4}, {4, 0}}}
+	s := p.Skeleton(0.5)
+	var paths []Path
+	for _, l := range s {
+		paths = append(paths, Path(l))
+	}
+	for _, pt := range []Point{{5, 5}, {5, 1}, {9, 5}, {5, 9}, {1, 5}} {
+		if _, dist := nearestOnPaths(pt, paths); dist > 0.5 {
+			t.Errorf("the skeleton does not reach %v", pt)
+		}
+	}
+	for _, l := range s {
+		for _, pt := range l {
+			if pt.Within(p) != Inside {
+				t.Errorf("%v is not inside the polygon", pt)
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SmoothMethod specifies the algorithm used to smooth a geometry.
+type SmoothMethod int
+
+const (
+	// Chaikin smooths geometries by corner cutting: in each iteration,
+	// each segment is replaced by the points one quarter and three
+	// quarters of the way along it. The result approaches a quadratic
+	// B-spline, and does not pass through the original vertices.
+	Chaikin SmoothMethod = iota
+
+	// CatmullRom smooths geometries by interpolating a centripetal
+	// Catmull-Rom spline through the original vertices. Each segment is
+	// divided into 2^iterations pieces.
+	CatmullRom
+)
+
+// Smooth returns a smoothed copy of g. LineStrings, MultiLineStrings,
+// Polygons, MultiPolygons, and the members of GeometryCollections are
+// smoothed, as are other Polygonal and Linear types, which are returned
+// as MultiPolygons and MultiLineStrings; other types are returned
+// unchanged. The ends of LineStrings are not moved.
+//
+// Polygon rings stay closed, and a ring is only smoothed as far as it can be
+// without intersecting itself or the other rings of its polygon, or
+// leaving any holes outside of the outer ring. Rings in different polygons
+// of a MultiPolygon are not checked against each other.
+func Smooth(g Geom, method SmoothMethod, iterations int) Geom {
+	if iterations < 0 {
+		panic(fmt.Errorf("geom: invalid number of smoothing iterations %d", iterations))
+	}
+	switch g := g.(type) {
+	case Point, MultiPoint, *Bounds:
+		return g
+	case LineString:
+		return LineString(smoothPath(Path(g), method, iterations, false))
+	case MultiLineString:
+		o := make(MultiLineString, len(g))
+		for i, l := range g {
+			o[i] = LineString(smoothPath(Path(l), method, iterations, false))
+		}
+		return o
+	case Polygon:
+		return smoothPolygon(g, method, iterations)
+	case MultiPolygon:
+		o := make(MultiPolygon, len(g))
+		for i, p := range g {
+			o[i] = smoothPolygon(p, method, iterations)
+		}
+		return o
+	case GeometryCollection:
+		o := make(GeometryCollection, len(g))
+		for i, gg := range g {
+			o[i] = Smooth(gg, method, iterations)
+		}
+		return o
+	case Polygonal:
+		return Smooth(MultiPolygon(g.Polygons()), method, iterations)
+	case Linear:
+		return Smooth(linearLines(g), method, iterations)
+	default:
+		panic(fmt.Errorf("geom: unsupported type %T", g))
+	}
+}
+
+// smoothPolygon smooths the rings of p one iteration at a time, keeping
+// the previous version of any ring whose smoothed version would make the
+// polygon invalid.
+func smoothPolygon(p Polygon, method SmoothMethod, iterations int) Polygon {
+	o := make(Polygon, len(p))
+	for i, r := range p {
+		o[i] = openRing(r)
+	}
+	// Catmull-Rom splines always pass through the original vertices, so
+	// they are calculated in one step and then checked.
+	steps, perStep := iterations, 1
+	if method == CatmullRom {
+		steps, perStep = 1, iterations
+	}
+	if iterations == 0 {
+		steps = 0
+	}
+	done := make([]bool, len(o))
+	for s := 0; s < steps; s++ {
+		for i := range o {
+			if done[i] || len(o[i]) < 3 {
+				continue
+			}
+			old := o[i]
+			o[i] = smoothPath(old, method, perStep, true)
+			if !smoothedRingsValid(o) {
+				o[i] = old
+				done[i] = true
+			}
+		}
+	}
+	for i, r := range o {
+		if len(r) > 0 {
+			o[i] = append(r, r[0])
+		}
+	}
+	return o
+}
+
+// smoothPath smooths p. If closed is true, p is treated as an open ring
+// (without a repeated closing point).
+func smoothPath(p Path, method SmoothMethod, iterations int, closed bool) Path {
+	if len(p) < 3 || iterations == 0 {
+		return append(Path{}, p...)
+	}
+	switch method {
+	case Chaikin:
+		for i := 0; i < iterations; i++ {
+			p = chaikin(p, closed)
+		}
+		return p
+	case CatmullRom:
+		return catmullRom(p, 1<<uint(iterations), closed)
+	default:
+		panic(fmt.Errorf("geom: invalid smoothing method %d", method))
+	}
+}
+
+// chaikin carries out one iteration of Chaikin's corner cutting algorithm.
+func chaikin(p Path, closed bool) Path {
+	o := make(Path, 0, 2*len(p))
+	n := len(p) - 1
+	if closed {
+		n = len(p)
+	} else {
+		o = append(o, p[0])
+	}
+	for i := 0; i < n; i++ {
+		a, b := p[i], p[(i+1)%len(p)]
+		o = append(o, interpolateSegment(a, b, 0.25), interpolateSegment(a, b, 0.75))
+	}
+	if !closed {
+		o = append(o, p[len(p)-1])
+	}
+	return o
+}
+
+// catmullRom interpolates a centripetal Catmull-Rom spline through p,
+// dividing each segment into pieces.
+func catmullRom(p Path, pieces int, closed bool) Path {
+	n := len(p)
+	at := func(i int) Point {
+		switch {
+		case closed:
+			return p[(i%n+n)%n]
+		case i < 0:
+			// Extrapolate beyond the ends of the line.
+			return Point{X: 2*p[0].X - p[1].X, Y: 2*p[0].Y - p[1].Y}
+		case i >= n:
+			return Point{X: 2*p[n-1].X - p[n-2].X, Y: 2*p[n-1].Y - p[n-2].Y}
+		default:
+			return p[i]
+		}
+	}
+	segments := n - 1
+	if closed {
+		segments = n
+	}
+	o := make(Path, 0, segments*pieces+1)
+	for i := 0; i < segments; i++ {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+		// Centripetal parameterization avoids cusps and self-intersections
+		// within a segment.
+		t0 := 0.
+		t1 := t0 + math.Sqrt(d(p0, p1))
+		t2 := t1 + math.Sqrt(d(p1, p2))
+		t3 := t2 + math.Sqrt(d(p2, p3))
+		o = append(o, p1)
+		for k := 1; k < pieces; k++ {
+			t := t1 + (t2-t1)*float64(k)/float64(pieces)
+			o = append(o, catmullRomPoint(p0, p1, p2, p3, t0, t1, t2, t3, t))
+		}
+	}
+	if !closed {
+		o = append(o, p[n-1])
+	}
+	return o
+}
+
+// catmullRomPoint evaluates the Catmull-Rom spline through p0-p3 with
+// knots t0-t3 at t using the Barry-Goldman pyramidal formulation.
+func catmullRomPoint(p0, p1, p2, p3 Point, t0, t1, t2, t3, t float64) Point {
+	lerp := func(a, b Point, ta, tb float64) Point {
+		if tb == ta {
+			return a
+		}
+		return interpolateSegment(a, b, (t-ta)/(tb-ta))
+	}
+	a1 := lerp(p0, p1, t0, t1)
+	a2 := lerp(p1, p2, t1, t2)
+	a3 := lerp(p2, p3, t2, t3)
+	b1 := lerp(a1, a2, t0, t2)
+	b2 := lerp(a2, a3, t1, t3)
+	return lerp(b1, b2, t1, t2)
+}
+
+// smoothedRingsValid returns whether the open rings of a polygon are
+// simple and the holes are still within the outer ring.
+func smoothedRingsValid(rings []Path) bool {
+	if !ringsSimple(rings) {
+		return false
+	}
+	outer := Polygon{rings[0]}
+	bounds := outer.ringBounds()
+	for _, r := range rings[1:] {
+		if len(r) > 0 && pointInPolygon(r[0], outer, bounds) != Inside {
+			return false
+		}
+	}
+	return true
+}
+
+// ringsSimple returns whether the open rings in rings neither intersect
+// themselves nor each other, other than where adjacent segments of the same
+// ring meet. It uses a sweep along the x axis so that only segments with
+// overlapping x ranges are compared.
+func ringsSimple(rings []Path) bool {
+	type ringSeg struct {
+		segment
+		ring, i, n int
+		minX, maxX float64
+	}
+	var segs []ringSeg
+	for r, ring := range rings {
+		for i := range ring {
+			s := segment{ring[i], ring[(i+1)%len(ring)]}
+			segs = append(segs, ringSeg{
+				segment: s, ring: r, i: i, n: len(ring),
+				minX: math.Min(s.start.X, s.end.X), maxX: math.Max(s.start.X, s.end.X),
+			})
+		}
+	}
+	sort.Slice(segs, func(i, j int) bool { return segs[i].minX < segs[j].minX })
+	for i, a := range segs {
+		for j := i + 1; j < len(segs) && segs[j].minX <= a.maxX; j++ {
+			b := segs[j]
+			if a.ring == b.ring && (b.i == (a.i+1)%a.n || a.i == (b.i+1)%a.n) {
+				// Adjacent segments only share an endpoint, unless
+				// they overlap.
+				if orient(a.start, a.end, b.start) == 0 && orient(a.start, a.end, b.end) == 0 {
+					if n, _, _ := findIntersection(a.segment, b.segment); n > 1 {
+						return false
+					}
+				}
+				continue
+			}
+			if n, _, _ := findIntersection(a.segment, b.segment); n > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
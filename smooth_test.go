@@ -0,0 +1,96 @@
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSmooth(t *testing.T) {
+	tests := []struct {
+		g          Geom
+		method     SmoothMethod
+		iterations int
+		want       Geom
+	}{
+		{
+			g:          LineString{{0, 0}, {4, 0}, {4, 4}},
+			method:     Chaikin,
+			iterations: 1,
+			want:       LineString{{0, 0}, {1, 0}, {3, 0}, {4, 1}, {4, 3}, {4, 4}},
+		},
+		{
+			g:          Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}},
+			method:     Chaikin,
+			iterations: 1,
+			want:       Polygon{{{1, 0}, {3, 0}, {4, 1}, {4, 3}, {3, 4}, {1, 4}, {0, 3}, {0, 1}, {1, 0}}},
+		},
+		{
+			g:          MultiLineString{{{0, 0}, {8, 0}}},
+			method:     Chaikin,
+			iterations: 3,
+			want:       MultiLineString{{{0, 0}, {8, 0}}},
+		},
+		{
+			g:          LineString{{0, 0}, {1, 0}, {2, 0}},
+			method:     CatmullRom,
+			iterations: 1,
+			want:       LineString{{0, 0}, {0.5, 0}, {1, 0}, {1.5, 0}, {2, 0}},
+		},
+		{
+			g:          Point{1, 2},
+			method:     CatmullRom,
+			iterations: 2,
+			want:       Point{1, 2},
+		},
+	}
+	for i, test := range tests {
+		have := Smooth(test.g, test.method, test.iterations)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestSmoothCatmullRom(t *testing.T) {
+	// The spline passes through the original vertices.
+	p := Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}
+	s := Smooth(p, CatmullRom, 2).(Polygon)
+	if len(s[0]) != 17 {
+		t.Fatalf("have %d points, want 17", len(s[0]))
+	}
+	for i, pt := range p[0] {
+		if s[0][4*i] != pt {
+			t.Errorf("point %d: have %v, want %v", 4*i, s[0][4*i], pt)
+		}
+	}
+	// It bulges outward between the corners of the square.
+	if mid := s[0][2]; mid.X != 2 || mid.Y >= 0 {
+		t.Errorf("midpoint: have %v", mid)
+	}
+}
+
+func TestSmoothTopology(t *testing.T) {
+	// The hole is near the corner of the outer ring, so cutting the corner
+	// would cross the hole.
+	crossing := Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{0.5, 0.5}, {0.5, 3}, {3, 0.5}, {0.5, 0.5}},
+	}
+	// Here, cutting the corner would leave the hole outside.
+	outside := Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{0.5, 0.5}, {0.5, 1.5}, {1.5, 0.5}, {0.5, 0.5}},
+	}
+	for i, p := range []Polygon{crossing, outside} {
+		s := Smooth(p, Chaikin, 2).(Polygon)
+		if !reflect.DeepEqual(s[0], p[0]) {
+			t.Errorf("%d: the outer ring should not have been smoothed: %v", i, s[0])
+		}
+		if len(s[1]) != 2*2*3+1 {
+			t.Errorf("%d: the hole should have been smoothed twice: %v", i, s[1])
+		}
+		if !s[1][0].Equals(s[1][len(s[1])-1]) {
+			t.Errorf("%d: the hole is not closed", i)
+		}
+	}
+}
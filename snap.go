@@ -0,0 +1,172 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// Snap returns a copy of g whose vertices and edges have been snapped to
+// reference, for example so that two datasets that were digitized
+// separately share boundaries exactly and can be overlaid without
+// creating slivers. Each vertex of g that is within tolerance of a vertex
+// of reference is moved to the nearest such vertex, and then each vertex
+// of reference that is within tolerance of an edge of g is inserted into
+// that edge. Points are only snapped to vertices.
+//
+// Consecutive duplicate points are removed from the result, and polygon
+// rings that collapse to fewer than three distinct points are dropped.
+// Polygonal and Linear types other than the slice types, such as
+// FlatMultiPolygon, are snapped as MultiPolygons and MultiLineStrings.
+// Large tolerances can cause the result to be invalid, so tolerance
+// should be smaller than the distance between nearby vertices.
+func Snap(g, reference Geom, tolerance float64) Geom {
+	if !(tolerance >= 0) {
+		panic(fmt.Errorf("geom: invalid snapping tolerance %g", tolerance))
+	}
+	s := snapper{tolerance: tolerance}
+	seen := make(map[Point]bool)
+	for _, path := range geomPaths(reference) {
+		for _, p := range path {
+			if !seen[p] {
+				seen[p] = true
+				s.refs = append(s.refs, p)
+			}
+		}
+	}
+	return s.snap(g)
+}
+
+// snapper snaps geometries to a set of reference vertices.
+type snapper struct {
+	refs      []Point
+	tolerance float64
+}
+
+func (s snapper) snap(g Geom) Geom {
+	switch g := g.(type) {
+	case Point:
+		return s.snapVertex(g)
+	case MultiPoint:
+		o := make(MultiPoint, len(g))
+		for i, p := range g {
+			o[i] = s.snapVertex(p)
+		}
+		return o
+	case LineString:
+		return LineString(s.snapPath(Path(g), false))
+	case MultiLineString:
+		o := make(MultiLineString, len(g))
+		for i, l := range g {
+			o[i] = LineString(s.snapPath(Path(l), false))
+		}
+		return o
+	case Polygon:
+		return s.snapPolygon(g)
+	case MultiPolygon:
+		o := make(MultiPolygon, 0, len(g))
+		for _, p := range g {
+			if sp := s.snapPolygon(p); len(sp) > 0 {
+				o = append(o, sp)
+			}
+		}
+		return o
+	case *Bounds:
+		return s.snapPolygon(g.Polygons()[0])
+	case GeometryCollection:
+		o := make(GeometryCollection, len(g))
+		for i, gg := range g {
+			o[i] = s.snap(gg)
+		}
+		return o
+	case Polygonal:
+		return s.snap(MultiPolygon(g.Polygons()))
+	case Linear:
+		return s.snap(linearLines(g))
+	default:
+		panic(fmt.Errorf("geom: unsupported type %T", g))
+	}
+}
+
+// snapVertex returns the reference vertex that is nearest to p, if it is
+// within tolerance, or p otherwise.
+func (s snapper) snapVertex(p Point) Point {
+	nearest, dist := p, math.Inf(1)
+	for _, r := range s.refs {
+		if dd := d(p, r); dd <= s.tolerance && dd < dist {
+			nearest, dist = r, dd
+		}
+	}
+	return nearest
+}
+
+// snapPolygon snaps the rings of p, dropping the rings that collapse. If
+// the outer ring collapses, the result is empty.
+func (s snapper) snapPolygon(p Polygon) Polygon {
+	o := make(Polygon, 0, len(p))
+	for i, r := range p {
+		sr := s.snapPath(r, true)
+		if len(openRing(sr)) < 3 {
+			if i == 0 {
+				return Polygon{}
+			}
+			continue
+		}
+		o = append(o, sr)
+	}
+	return o
+}
+
+// snapPath snaps the vertices and segments of path. If ring is true,
+// path is treated as a polygon ring, which is closed in the result if it
+// is closed in path.
+func (s snapper) snapPath(path Path, ring bool) Path {
+	closed := ring && len(path) > 1 && path[0].Equals(path[len(path)-1])
+	if closed {
+		path = path[:len(path)-1]
+	}
+	o := make(Path, 0, len(path)+1)
+	for _, p := range path {
+		o = Path(appendNew(LineString(o), s.snapVertex(p)))
+	}
+	if ring && len(o) > 1 && o[0].Equals(o[len(o)-1]) {
+		o = o[:len(o)-1]
+	}
+	if ring && len(o) > 0 {
+		// Include the closing segment.
+		o = append(o, o[0])
+	}
+
+	// Insert the reference vertices that are near segments of the path.
+	for _, r := range s.refs {
+		if len(o) < 2 || pathHasVertex(o, r) {
+			continue
+		}
+		seg, dist := -1, math.Inf(1)
+		for i := 0; i < len(o)-1; i++ {
+			f := projectSegment(r, o[i], o[i+1])
+			if f <= 0 || f >= 1 {
+				continue
+			}
+			if dd := d(r, interpolateSegment(o[i], o[i+1], f)); dd <= s.tolerance && dd < dist {
+				seg, dist = i, dd
+			}
+		}
+		if seg >= 0 {
+			o = append(o[:seg+1], append(Path{r}, o[seg+1:]...)...)
+		}
+	}
+	if ring && !closed && len(o) > 0 {
+		o = o[:len(o)-1]
+	}
+	return o
+}
+
+// pathHasVertex returns whether p is a vertex of path.
+func pathHasVertex(path Path, p Point) bool {
+	for _, v := range path {
+		if v.Equals(p) {
+			return true
+		}
+	}
+	return false
+}
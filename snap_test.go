@@ -0,0 +1,55 @@
+package geom
+
+import "testing"
+
+func TestSnap(t *testing.T) {
+	ref := Polygon{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}}}
+	tests := []struct {
+		g, want Geom
+	}{
+		{
+			g:    Point{X: 10.05, Y: 9.9},
+			want: Point{X: 10, Y: 10},
+		},
+		{
+			g:    MultiPoint{{X: 5, Y: 0.05}, {X: 0.1, Y: 0}},
+			want: MultiPoint{{X: 5, Y: 0.05}, {X: 0, Y: 0}},
+		},
+		{ // The line is snapped to the corner and the corner is inserted.
+			g:    LineString{{X: 5, Y: -5}, {X: 10.1, Y: 0.1}, {X: 15, Y: 5}},
+			want: LineString{{X: 5, Y: -5}, {X: 10, Y: 0}, {X: 15, Y: 5}},
+		},
+		{
+			g:    LineString{{X: -5, Y: 0.1}, {X: 15, Y: 0.1}},
+			want: LineString{{X: -5, Y: 0.1}, {X: 0, Y: 0}, {X: 10, Y: 0}, {X: 15, Y: 0.1}},
+		},
+		{ // A neighbor whose shared edge is slightly off.
+			g:    Polygon{{{X: 10.1, Y: -0.1}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 9.9, Y: 10.05}, {X: 10.1, Y: -0.1}}},
+			want: Polygon{{{X: 10, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}}},
+		},
+		{ // Reference vertices on an edge are inserted into it.
+			g:    MultiLineString{{{X: 10, Y: 15}, {X: 10, Y: -5}}},
+			want: MultiLineString{{{X: 10, Y: 15}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 10, Y: -5}}},
+		},
+		{ // A sliver that collapses.
+			g:    Polygon{{{X: 0, Y: 0.1}, {X: 10, Y: 0.1}, {X: 10, Y: -0.1}}},
+			want: Polygon{},
+		},
+		{ // A hole that is inserted into the edge of the reference.
+			g: Polygon{
+				{{X: -10, Y: -10}, {X: 20, Y: -10}, {X: 20, Y: 20}, {X: -10, Y: 20}},
+				{{X: 0.1, Y: 0}, {X: 0, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}},
+			},
+			want: Polygon{
+				{{X: -10, Y: -10}, {X: 20, Y: -10}, {X: 20, Y: 20}, {X: -10, Y: 20}},
+				{{X: 0, Y: 0}, {X: 0, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}},
+			},
+		},
+	}
+	for i, test := range tests {
+		have := Snap(test.g, ref, 0.2)
+		if !have.Similar(test.want, 1e-12) {
+			t.Errorf("%d: have %v, want %v", i, have, test.want)
+		}
+	}
+}
@@ -0,0 +1,488 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Triangulate divides p into triangles, for example for rendering with
+// OpenGL or WebGL. The first ring of p is the outer boundary and the
+// remaining rings are holes, which are joined to the outer boundary by
+// bridges before the result is triangulated by ear clipping, following
+// the earcut library by Mapbox.
+//
+// vertices holds the points of the rings of p, in order and without
+// repeated closing points, and each group of three consecutive values in
+// indices is a triangle, with its corners in counterclockwise order
+// given as indices into vertices. If p is self-intersecting, the
+// triangles may not cover it exactly.
+func Triangulate(p Polygon) (vertices []Point, indices []int) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	var holeStarts []int
+	for i, r := range p {
+		if i > 0 {
+			holeStarts = append(holeStarts, len(vertices))
+		}
+		vertices = append(vertices, openRing(r)...)
+	}
+	ringEnd := func(i int) int {
+		if i < len(holeStarts) {
+			return holeStarts[i]
+		}
+		return len(vertices)
+	}
+
+	outer := earLinkedList(vertices, 0, ringEnd(0), true)
+	if outer == nil || outer.next == outer.prev {
+		return vertices, nil
+	}
+	if len(holeStarts) > 0 {
+		outer = eliminateHoles(vertices, holeStarts, ringEnd, outer)
+	}
+	earcutLinked(outer, &indices, 0)
+	return vertices, indices
+}
+
+// earNode is a vertex in a circular doubly linked list of ring vertices.
+type earNode struct {
+	i          int // The index of the vertex.
+	Point          // The location of the vertex.
+	prev, next *earNode
+	steiner    bool // Whether the node is a hole that is a single point.
+}
+
+// earArea returns twice the signed area of triangle pqr, which is negative
+// if p, q and r are in counterclockwise order.
+func earArea(p, q, r *earNode) float64 { return -orient(p.Point, q.Point, r.Point) }
+
+// earLinkedList creates a circular linked list from vertices[start:end],
+// in counterclockwise order if ccw is true and clockwise order otherwise.
+func earLinkedList(vertices []Point, start, end int, ccw bool) *earNode {
+	var sum float64
+	for i, j := start, end-1; i < end; j, i = i, i+1 {
+		sum += (vertices[j].X - vertices[i].X) * (vertices[i].Y + vertices[j].Y)
+	}
+	var last *earNode
+	if ccw == (sum > 0) {
+		for i := start; i < end; i++ {
+			last = insertEarNode(i, vertices[i], last)
+		}
+	} else {
+		for i := end - 1; i >= start; i-- {
+			last = insertEarNode(i, vertices[i], last)
+		}
+	}
+	if last != nil && last.Point.Equals(last.next.Point) {
+		removeEarNode(last)
+		last = last.next
+	}
+	return last
+}
+
+func insertEarNode(i int, p Point, last *earNode) *earNode {
+	n := &earNode{i: i, Point: p}
+	if last == nil {
+		n.prev, n.next = n, n
+	} else {
+		n.next, n.prev = last.next, last
+		last.next.prev = n
+		last.next = n
+	}
+	return n
+}
+
+func removeEarNode(n *earNode) {
+	n.next.prev = n.prev
+	n.prev.next = n.next
+}
+
+// filterEarPoints removes duplicate and collinear points between start
+// and end.
+func filterEarPoints(start, end *earNode) *earNode {
+	if start == nil {
+		return nil
+	}
+	if end == nil {
+		end = start
+	}
+	p := start
+	for {
+		again := false
+		if !p.steiner && (p.Point.Equals(p.next.Point) || earArea(p.prev, p, p.next) == 0) {
+			removeEarNode(p)
+			p = p.prev
+			end = p
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+// earcutLinked clips the ears from the ring starting at ear and appends
+// them to indices. If it runs out of ears, it tries again after removing
+// collinear points (pass 1), then after curing small self-intersections
+// (pass 2), and finally by splitting the ring in two.
+func earcutLinked(ear *earNode, indices *[]int, pass int) {
+	if ear == nil {
+		return
+	}
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+		if isEar(ear) {
+			*indices = append(*indices, prev.i, ear.i, next.i)
+			removeEarNode(ear)
+			ear = next.next
+			stop = next.next
+			continue
+		}
+		ear = next
+		if ear == stop {
+			switch pass {
+			case 0:
+				earcutLinked(filterEarPoints(ear, nil), indices, 1)
+			case 1:
+				ear = cureLocalIntersections(filterEarPoints(ear, nil), indices)
+				earcutLinked(ear, indices, 2)
+			case 2:
+				splitEarcut(ear, indices)
+			}
+			return
+		}
+	}
+}
+
+// isEar returns whether the triangle formed by ear and its neighbors is
+// convex and contains no other reflex vertices of the ring.
+func isEar(ear *earNode) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if earArea(a, b, c) >= 0 {
+		return false // Reflex
+	}
+	for p := c.next; p != a; p = p.next {
+		if earPointInTriangle(a.Point, b.Point, c.Point, p.Point) &&
+			earArea(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// cureLocalIntersections removes small self-intersections by clipping the
+// triangles they form.
+func cureLocalIntersections(start *earNode, indices *[]int) *earNode {
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+		if !a.Point.Equals(b.Point) && earSegmentsIntersect(a, p, p.next, b) &&
+			locallyInside(a, b) && locallyInside(b, a) {
+			*indices = append(*indices, a.i, p.i, b.i)
+			removeEarNode(p)
+			removeEarNode(p.next)
+			p, start = b, b
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return filterEarPoints(p, nil)
+}
+
+// splitEarcut looks for a valid diagonal that divides the ring starting at
+// start into two, and triangulates each of the two parts.
+func splitEarcut(start *earNode, indices *[]int) {
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				c := splitEarPolygon(a, b)
+				a = filterEarPoints(a, a.next)
+				c = filterEarPoints(c, c.next)
+				earcutLinked(a, indices, 0)
+				earcutLinked(c, indices, 0)
+				return
+			}
+		}
+		a = a.next
+		if a == start {
+			return
+		}
+	}
+}
+
+// eliminateHoles links the holes into the outer ring, from left to right.
+func eliminateHoles(vertices []Point, holeStarts []int, ringEnd func(int) int, outer *earNode) *earNode {
+	var queue []*earNode
+	for i, start := range holeStarts {
+		list := earLinkedList(vertices, start, ringEnd(i+1), false)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, leftmostEarNode(list))
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].X < queue[j].X })
+	for _, h := range queue {
+		outer = eliminateHole(h, outer)
+	}
+	return outer
+}
+
+// eliminateHole finds a bridge between hole and the outer ring and links
+// them.
+func eliminateHole(hole, outer *earNode) *earNode {
+	bridge := findHoleBridge(hole, outer)
+	if bridge == nil {
+		return outer
+	}
+	bridgeReverse := splitEarPolygon(bridge, hole)
+	filterEarPoints(bridgeReverse, bridgeReverse.next)
+	return filterEarPoints(bridge, bridge.next)
+}
+
+// findHoleBridge uses David Eberly's algorithm to find a vertex of the
+// outer ring that can be connected to hole by a diagonal.
+func findHoleBridge(hole, outer *earNode) *earNode {
+	p := outer
+	hx, hy := hole.X, hole.Y
+	qx := math.Inf(-1)
+	var m *earNode
+
+	// Find the segment of the outer ring that is closest to the left of
+	// the hole's leftmost point.
+	for {
+		if hy <= p.Y && hy >= p.next.Y && p.next.Y != p.Y {
+			x := p.X + (hy-p.Y)*(p.next.X-p.X)/(p.next.Y-p.Y)
+			if x <= hx && x > qx {
+				qx = x
+				m = p.next
+				if p.X < p.next.X {
+					m = p
+				}
+				if x == hx {
+					// The hole touches the outer segment.
+					return m
+				}
+			}
+		}
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
+
+	// Look for points inside the triangle formed by the hole point, the
+	// segment intersection and the segment endpoint, and choose the one
+	// with the smallest angle to the ray, if there is one.
+	stop := m
+	mx, my := m.X, m.Y
+	tanMin := math.Inf(1)
+	p = m
+	for {
+		a, c := Point{X: qx, Y: hy}, Point{X: hx, Y: hy}
+		if hy < my {
+			a, c = c, a
+		}
+		if hx >= p.X && p.X >= mx && hx != p.X &&
+			earPointInTriangle(a, Point{X: mx, Y: my}, c, p.Point) {
+			tan := math.Abs(hy-p.Y) / (hx - p.X)
+			if locallyInside(p, hole) &&
+				(tan < tanMin || (tan == tanMin && (p.X > m.X || (p.X == m.X && sectorContainsSector(m, p))))) {
+				m = p
+				tanMin = tan
+			}
+		}
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+	return m
+}
+
+// sectorContainsSector returns whether the sector at vertex m contains
+// the sector at vertex p, where m and p are at the same location.
+func sectorContainsSector(m, p *earNode) bool {
+	return earArea(m.prev, m, p.prev) < 0 && earArea(p.next, m, m.next) < 0
+}
+
+func leftmostEarNode(start *earNode) *earNode {
+	p, leftmost := start, start
+	for {
+		if p.X < leftmost.X || (p.X == leftmost.X && p.Y < leftmost.Y) {
+			leftmost = p
+		}
+		p = p.next
+		if p == start {
+			return leftmost
+		}
+	}
+}
+
+// earPointInTriangle returns whether p is inside or on the edge of
+// triangle abc.
+func earPointInTriangle(a, b, c, p Point) bool {
+	return (c.X-p.X)*(a.Y-p.Y) >= (a.X-p.X)*(c.Y-p.Y) &&
+		(a.X-p.X)*(b.Y-p.Y) >= (b.X-p.X)*(a.Y-p.Y) &&
+		(b.X-p.X)*(c.Y-p.Y) >= (c.X-p.X)*(b.Y-p.Y)
+}
+
+// isValidDiagonal returns whether a diagonal from a to b lies inside the
+// ring without crossing it.
+func isValidDiagonal(a, b *earNode) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !earIntersectsRing(a, b) &&
+		(locallyInside(a, b) && locallyInside(b, a) && earMiddleInside(a, b) &&
+			(earArea(a.prev, a, b.prev) != 0 || earArea(a, b.prev, b) != 0) ||
+			a.Point.Equals(b.Point) && earArea(a.prev, a, a.next) > 0 && earArea(b.prev, b, b.next) > 0)
+}
+
+func earSign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// earSegmentsIntersect returns whether segments p1q1 and p2q2 intersect
+// or touch.
+func earSegmentsIntersect(p1, q1, p2, q2 *earNode) bool {
+	o1 := earSign(earArea(p1, q1, p2))
+	o2 := earSign(earArea(p1, q1, q2))
+	o3 := earSign(earArea(p2, q2, p1))
+	o4 := earSign(earArea(p2, q2, q1))
+	return o1 != o2 && o3 != o4 ||
+		o1 == 0 && earOnSegment(p1, p2, q1) ||
+		o2 == 0 && earOnSegment(p1, q2, q1) ||
+		o3 == 0 && earOnSegment(p2, p1, q2) ||
+		o4 == 0 && earOnSegment(p2, q1, q2)
+}
+
+// earOnSegment returns whether q, which is collinear with p and r, is
+// between them.
+func earOnSegment(p, q, r *earNode) bool {
+	return q.X <= math.Max(p.X, r.X) && q.X >= math.Min(p.X, r.X) &&
+		q.Y <= math.Max(p.Y, r.Y) && q.Y >= math.Min(p.Y, r.Y)
+}
+
+// earIntersectsRing returns whether the diagonal ab intersects any edge of
+// the ring that does not touch a or b.
+func earIntersectsRing(a, b *earNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i &&
+			earSegmentsIntersect(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			return false
+		}
+	}
+}
+
+// locallyInside returns whether the diagonal ab is inside the ring in the
+// neighborhood of a.
+func locallyInside(a, b *earNode) bool {
+	if earArea(a.prev, a, a.next) < 0 {
+		return earArea(a, b, a.next) >= 0 && earArea(a, a.prev, b) >= 0
+	}
+	return earArea(a, b, a.prev) < 0 || earArea(a, a.next, b) < 0
+}
+
+// earMiddleInside returns whether the midpoint of the diagonal ab is
+// inside the ring.
+func earMiddleInside(a, b *earNode) bool {
+	p := a
+	inside := false
+	px, py := (a.X+b.X)/2, (a.Y+b.Y)/2
+	for {
+		if (p.Y > py) != (p.next.Y > py) && p.next.Y != p.Y &&
+			px < (p.next.X-p.X)*(py-p.Y)/(p.next.Y-p.Y)+p.X {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+// splitEarPolygon links a and b with a diagonal, splitting the ring in
+// two. If a and b are in different rings, it merges them instead. It
+// returns the copy of b in the new ring.
+func splitEarPolygon(a, b *earNode) *earNode {
+	a2 := &earNode{i: a.i, Point: a.Point}
+	b2 := &earNode{i: b.i, Point: b.Point}
+	an, bp := a.next, b.prev
+
+	a.next, b.prev = b, a
+	a2.next, an.prev = an, a2
+	b2.next, a2.prev = a2, b2
+	bp.next, b2.prev = b2, bp
+	return b2
+}
+
+// RandomPoints returns n points that are uniformly distributed within pg,
+// using rng, for example for dot density maps. It panics if pg has zero
+// area and n is greater than zero.
+func RandomPoints(pg Polygonal, n int, rng *rand.Rand) MultiPoint {
+	if n < 0 {
+		panic(fmt.Errorf("geom: invalid number of points %d", n))
+	}
+	if n == 0 {
+		return MultiPoint{}
+	}
+	var tris [][3]Point
+	var cumArea []float64
+	var total float64
+	for _, p := range pg.Polygons() {
+		vertices, indices := Triangulate(p)
+		for i := 0; i < len(indices); i += 3 {
+			t := [3]Point{vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]}
+			a := orient(t[0], t[1], t[2]) / 2
+			if a <= 0 {
+				continue
+			}
+			total += a
+			tris = append(tris, t)
+			cumArea = append(cumArea, total)
+		}
+	}
+	if total == 0 {
+		panic(fmt.Errorf("geom: can't place points in a polygon with zero area"))
+	}
+	o := make(MultiPoint, n)
+	for i := range o {
+		t := tris[sort.SearchFloat64s(cumArea, rng.Float64()*total)]
+		r1, r2 := rng.Float64(), rng.Float64()
+		if r1+r2 > 1 {
+			r1, r2 = 1-r1, 1-r2
+		}
+		o[i] = Point{
+			X: t[0].X + r1*(t[1].X-t[0].X) + r2*(t[2].X-t[0].X),
+			Y: t[0].Y + r1*(t[1].Y-t[0].Y) + r2*(t[2].Y-t[0].Y),
+		}
+	}
+	return o
+}
@@ -0,0 +1,87 @@
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTriangulate(t *testing.T) {
+	tests := []struct {
+		p     Polygon
+		ntris int
+	}{
+		{ // Square, closed ring.
+			p:     Polygon{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}}},
+			ntris: 2,
+		},
+		{ // Clockwise L shape, open ring.
+			p:     Polygon{{{X: 0, Y: 0}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 0}}},
+			ntris: 4,
+		},
+		{ // Square with a hole.
+			p: Polygon{
+				{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+				{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}, {X: 1, Y: 3}},
+			},
+			ntris: 8,
+		},
+		{ // Two holes, one of them touching the outer ring.
+			p: Polygon{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+				{{X: 2, Y: 2}, {X: 2, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 2}},
+				{{X: 6, Y: 0}, {X: 7, Y: 8}, {X: 8, Y: 0}},
+			},
+			ntris: 11,
+		},
+		{ // Collinear points only.
+			p: Polygon{{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}},
+		},
+		{},
+	}
+	for i, test := range tests {
+		vertices, indices := Triangulate(test.p)
+		if len(indices) != 3*test.ntris {
+			t.Errorf("%d: have %d triangles, want %d", i, len(indices)/3, test.ntris)
+		}
+		var area float64
+		for j := 0; j < len(indices); j += 3 {
+			a := orient(vertices[indices[j]], vertices[indices[j+1]], vertices[indices[j+2]]) / 2
+			if a <= 0 {
+				t.Errorf("%d: triangle %d is not counterclockwise", i, j/3)
+			}
+			area += a
+		}
+		if want := test.p.Area(); math.Abs(area-want) > 1e-12 {
+			t.Errorf("%d: area: have %g, want %g", i, area, want)
+		}
+	}
+}
+
+func TestRandomPoints(t *testing.T) {
+	mp := MultiPolygon{
+		{
+			{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0}},
+			{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}, {X: 1, Y: 3}, {X: 1, Y: 1}},
+		},
+		{{{X: 10, Y: 0}, {X: 14, Y: 0}, {X: 14, Y: 1}, {X: 10, Y: 1}, {X: 10, Y: 0}}},
+	}
+	const n = 10000
+	points := RandomPoints(mp, n, rand.New(rand.NewSource(1)))
+	if len(points) != n {
+		t.Fatalf("have %d points, want %d", len(points), n)
+	}
+	var second int
+	for _, p := range points {
+		if p.Within(mp) == Outside {
+			t.Fatalf("point %v is outside of the polygon", p)
+		}
+		if p.X > 5 {
+			second++
+		}
+	}
+	// The second polygon holds 4 of the 16 units of area.
+	if f := float64(second) / n; math.Abs(f-0.25) > 0.02 {
+		t.Errorf("fraction in second polygon: have %g, want 0.25", f)
+	}
+}
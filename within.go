@@ -104,5 +104,6 @@ func rayIntersectsSegment(p, a, b Point) bool {
 			return true
 		}
 	}
-	return (p.Y-a.Y)/(p.X-a.X) >= (b.Y-a.Y)/(b.X-a.X)
+	// The ray crosses the segment if p is to the left of it.
+	return orient(a, b, p) >= 0
 }
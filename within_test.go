@@ -1,6 +1,9 @@
 package geom
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestWithin1(t *testing.T) {
 	p := Point{620858.7034230313, -1.3334340701764394e+06}
@@ -103,3 +106,26 @@ func TestWithin4(t *testing.T) {
 		t.Errorf("%v should be within %v", p, poly)
 	}
 }
+
+// Points very close to an edge should be classified consistently.
+func TestWithinNearEdge(t *testing.T) {
+	poly := Polygon{{
+		Point{X: 12, Y: 12},
+		Point{X: 24, Y: 24},
+		Point{X: 0, Y: 24},
+	}}
+	if p := (Point{X: 18, Y: 18}); p.Within(poly) != OnEdge {
+		t.Errorf("%v should be on edge of %v", p, poly)
+	}
+	right, left := 18., 18.
+	for i := 0; i < 100; i++ {
+		right = math.Nextafter(right, math.Inf(1))
+		left = math.Nextafter(left, math.Inf(-1))
+		if p := (Point{X: right, Y: 18}); p.Within(poly) != Outside {
+			t.Errorf("%v should be outside of %v", p, poly)
+		}
+		if p := (Point{X: left, Y: 18}); p.Within(poly) != Inside {
+			t.Errorf("%v should be within %v", p, poly)
+		}
+	}
+}